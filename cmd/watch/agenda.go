@@ -0,0 +1,401 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ValenTheRed/watch/internal/widget"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Agenda parses the arguments of the agenda command, and returns a
+// function that sets up app to time the items of the agenda in the file
+// given in args.
+func Agenda(args []string) (func(app *tview.Application) *tview.Application, error) {
+	fs := newFlagSet("agenda")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return nil, fmt.Errorf("agenda takes an agenda file")
+	}
+	items, err := loadAgenda(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+	return func(app *tview.Application) *tview.Application {
+		return AgendaApp(app, items)
+	}, nil
+}
+
+// agendaItem is an item of the agenda of a meeting.
+type agendaItem struct {
+	// title is what the item is about, and owner who leads it, if
+	// anyone.
+	title, owner string
+
+	// planned is the time box of the item, and actual the time it took,
+	// once it is done.
+	planned, actual time.Duration
+
+	// started is the time the item started at, once it has.
+	started time.Time
+}
+
+// String returns the title of the item, followed by it's owner, if any.
+func (item agendaItem) String() string {
+	if item.owner == "" {
+		return item.title
+	}
+	return fmt.Sprintf("%s (%s)", item.title, item.owner)
+}
+
+// loadAgenda returns the items of the agenda in file, one per line, as
+// the time box, in any of the formats of ParseDuration, the title, and
+// the owner, if any, prefixed with '@', like
+//
+//	10m Roadmap @ann
+//
+// Blank lines and lines starting with '#' are ignored.
+func loadAgenda(file string) ([]agendaItem, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("agenda: %v", err)
+	}
+	defer f.Close()
+
+	items, err := parseAgenda(f)
+	if err != nil {
+		return nil, fmt.Errorf("agenda: %s: %v", file, err)
+	}
+	return items, nil
+}
+
+// parseAgenda returns the items of the agenda read from r, in the format
+// of loadAgenda.
+func parseAgenda(r io.Reader) ([]agendaItem, error) {
+	var items []agendaItem
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		seconds, err := ParseDuration(fields[0])
+		if err != nil || seconds == 0 {
+			return nil, fmt.Errorf("line %d: %q is not a time box", n, fields[0])
+		}
+		item := agendaItem{planned: time.Duration(seconds) * time.Second}
+		fields = fields[1:]
+		if k := len(fields) - 1; k >= 0 && strings.HasPrefix(fields[k], "@") {
+			item.owner = strings.TrimPrefix(fields[k], "@")
+			fields = fields[:k]
+		}
+		item.title = strings.Join(fields, " ")
+		items = append(items, item)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no items")
+	}
+	return items, nil
+}
+
+// formatOffset returns d, rounded to the second, with it's sign.
+func formatOffset(d time.Duration) string {
+	s := int(d.Round(time.Second) / time.Second)
+	if s < 0 {
+		return "-" + widget.SecondWithColons(-s)
+	}
+	return "+" + widget.SecondWithColons(s)
+}
+
+// agendaSummary returns the planned and the actual time of the items
+// done, and how far off the plan each was.
+func agendaSummary(items []agendaItem) string {
+	return string(writeToBytes(func(out io.Writer) error {
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "item\towner\tplanned\tactual\toff by\t")
+		var planned, actual time.Duration
+		for _, item := range items {
+			if item.started.IsZero() {
+				continue
+			}
+			// The current item is cut off at now.
+			took := item.actual
+			if took == 0 {
+				took = time.Since(item.started)
+			}
+			planned += item.planned
+			actual += took
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", item.title, item.owner,
+				widget.SecondWithColons(int(item.planned/time.Second)),
+				widget.SecondWithColons(int(took/time.Second)),
+				formatOffset(took-item.planned))
+		}
+		fmt.Fprintf(w, "total\t\t%s\t%s\t%s\t\n",
+			widget.SecondWithColons(int(planned/time.Second)),
+			widget.SecondWithColons(int(actual/time.Second)),
+			formatOffset(actual-planned))
+		return w.Flush()
+	}))
+}
+
+// AgendaApp returns app after setting the root to time items, queued one
+// after the other. The clock counts down the time box of the current
+// item and, once it has run out, counts up the time it has run over by.
+// An item that runs over, or finishes early, carries over to the end
+// times of the items after it.
+func AgendaApp(app *tview.Application, items []agendaItem) *tview.Application {
+	durations := make([]int, len(items))
+	titles := make([]string, len(items))
+	for i, item := range items {
+		durations[i] = int(item.planned / time.Second)
+		titles[i] = item.String()
+	}
+	q := widget.NewQueue(durations...)
+	q.SetTitles(titles...)
+	// The items run in order, and are moved on from with the next key.
+	q.SetSelectable(false, false)
+
+	// head is the current item, or the count of the items once they are
+	// all done.
+	head := 0
+	start := time.Now()
+	items[head].started = start
+
+	// The meeting is planned to end after the time boxes of every item.
+	plannedEnd := start
+	for _, item := range items {
+		plannedEnd = plannedEnd.Add(item.planned)
+	}
+
+	// deadline returns the time the current item's time box ends at.
+	var deadline = func() time.Time {
+		return items[head].started.Add(items[head].planned)
+	}
+	// projectedEnd returns the time the meeting is projected to end at,
+	// as of now, with the items after the current one following it
+	// with their time boxes.
+	var projectedEnd = func(now time.Time) time.Time {
+		if head == len(items) {
+			last := items[len(items)-1]
+			return last.started.Add(last.actual)
+		}
+		end := deadline()
+		if end.Before(now) {
+			end = now
+		}
+		for _, item := range items[head+1:] {
+			end = end.Add(item.planned)
+		}
+		return end
+	}
+
+	c := newClock(widget.NewUntil(deadline()))
+	// Keep the fields of the farthest the clock has been from the
+	// deadline.
+	longest := 0
+	nextFormat := formatCycle(func() int {
+		if v := int(c.Value() / time.Second); v > longest {
+			longest = v
+		}
+		return longest
+	}, nil, c)
+	// setFormat sets the clock, and the time boxes of the queue, to the
+	// next format.
+	var setFormat = func() {
+		if t := nextFormat(); t != nil {
+			q.SetDurationFormat(t.Seconds)
+		} else {
+			q.SetDurationFormat(widget.SecondWithColons)
+		}
+	}
+	setFormat()
+
+	label := tview.NewTextView()
+	label.SetTextAlign(tview.AlignCenter)
+	// update updates the end times of the items, and the label, to now.
+	var update = func(now time.Time) {
+		text := ""
+		// Once every item is done, they all keep the time they ended
+		// at.
+		remaining := time.Duration(0)
+		if head < len(items) {
+			if remaining = deadline().Sub(now); remaining < 0 {
+				remaining = 0
+			}
+			text = items[head].String()
+			if c.Passed() {
+				text += " · over time"
+			}
+			if head+1 < len(items) {
+				text += " · next: " + items[head+1].String()
+			}
+		}
+		q.SetEndTimes(remaining)
+		end := projectedEnd(now)
+		text += fmt.Sprintf("\nends at %s (%s on the plan)", end.Format("15:04"),
+			formatOffset(end.Sub(plannedEnd)))
+		label.SetText(text)
+	}
+	update(start)
+
+	summary := tview.NewTextView()
+	summary.SetTextAlign(tview.AlignCenter)
+	summary.SetBorder(true)
+	summary.SetTitle(" summary ")
+
+	passed := false
+	// updated is the second the end times were last updated at. The
+	// clock follows the wall clock, so it changes many times a second,
+	// but the end times only by the second.
+	var updated time.Time
+	c.Changed = func() {
+		app.QueueUpdateDraw(func() {
+			now := time.Now()
+			if !passed && c.Passed() {
+				passed = true
+				playChime()
+				c.TextColor = ColorWorst
+			}
+			if second := now.Truncate(time.Second); !second.Equal(updated) {
+				updated = second
+				update(now)
+			}
+		})
+	}
+
+	type info struct {
+		km     widget.KeyMap
+		action func()
+	}
+	interactions := struct {
+		next, format, quit info
+	}{
+		next: info{
+			km: widget.KeyMap{Key: "n", Desc: "next item"},
+		},
+		format: info{
+			km: widget.KeyMap{Key: "f", Desc: "format"},
+		},
+		quit: info{
+			km: widget.KeyMap{Key: "q", Desc: "quit"},
+		},
+	}
+
+	pages := tview.NewPages()
+	interactions.next.action = func() {
+		if head == len(items) {
+			return
+		}
+		now := time.Now()
+		items[head].actual = now.Sub(items[head].started)
+		q.Done()
+		head++
+		if head == len(items) {
+			c.Stop()
+			update(now)
+			summary.SetText(agendaSummary(items))
+			pages.ShowPage("summary")
+			return
+		}
+		items[head].started = now
+		q.Next()
+		passed = false
+		c.TextColor = ColorForeground
+		c.SetTarget(deadline())
+		update(now)
+	}
+	interactions.format.action = func() {
+		setFormat()
+	}
+	interactions.quit.action = func() {
+		app.Stop()
+	}
+
+	// The summary is only worth printing once an item is done.
+	Cleanup = func() error {
+		if head > 0 {
+			fmt.Print(agendaSummary(items))
+		}
+		return nil
+	}
+
+	hv := widget.NewHelpView([]widget.KeyMap{
+		interactions.next.km, interactions.format.km,
+		interactions.quit.km,
+	})
+	hv.SetDynamicColors(true)
+	hv.SetTextAlign(tview.AlignCenter)
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'n':
+				interactions.next.action()
+				return nil
+			case 'f':
+				interactions.format.action()
+				return nil
+			case 'q':
+				interactions.quit.action()
+				return nil
+			}
+		}
+		return event
+	})
+
+	f := tview.NewFlex().SetDirection(tview.FlexRow)
+	f.AddItem(c, 0, 2, false)
+	f.AddItem(label, 2, 0, false)
+	f.AddItem(hv, 2, 1, false)
+
+	c.SetVerticalAlign(widget.AlignCenter)
+	c.SetBorderPadding(1, 1, 2, 2)
+
+	root := tview.NewFlex()
+	root.AddItem(q, 0, 1, true)
+	root.AddItem(f, 0, 2, false)
+
+	SetTheme = func() {
+		q.SetBorder(true)
+		q.SetBorderColor(ColorSecondary)
+		q.SetBackgroundColor(ColorBackground)
+		q.SetSelectedStyle(tcell.StyleDefault.Background(ColorPrimary))
+		q.SetHeaderStyle(tcell.StyleDefault.Foreground(ColorForeground))
+		q.SetUnderlineStyle(tcell.StyleDefault.Foreground(ColorSecondary))
+		q.SetCellStyle(tcell.StyleDefault.Foreground(ColorForeground))
+		label.SetBackgroundColor(ColorBackground)
+		label.SetTextColor(ColorSecondary)
+		summary.SetBackgroundColor(ColorBackground)
+		summary.SetBorderColor(ColorSecondary)
+		summary.SetTextColor(ColorForeground)
+		c.SetBackgroundColor(ColorBackground)
+		hv.SetBackgroundColor(ColorBackground)
+		c.TextColor = ColorForeground
+		c.ShadowColor = ColorShadow
+		hv.SetKeyStyle(tcell.StyleDefault.Foreground(ColorSurface))
+		hv.SetDescStyle(tcell.StyleDefault.Foreground(ColorBorder))
+		hv.SetSeparatorStyle(tcell.StyleDefault.Foreground(ColorBorder))
+	}
+
+	c.Start()
+	pages.AddPage("agenda", root, true, true)
+	pages.AddPage("summary", tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(summary, len(items)+4, 0, false).
+			AddItem(nil, 0, 1, false), 0, 2, false).
+		AddItem(nil, 0, 1, false), true, false)
+
+	return app.SetRoot(pages, true)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAgenda(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    []agendaItem
+		wantErr bool
+	}{
+		{
+			name: "items",
+			src:  "5m  Welcome @ann\n20m Roadmap for Q3 @bob\n1:30 Questions\n",
+			want: []agendaItem{
+				{title: "Welcome", owner: "ann", planned: 5 * time.Minute},
+				{title: "Roadmap for Q3", owner: "bob", planned: 20 * time.Minute},
+				{title: "Questions", planned: 90 * time.Second},
+			},
+		},
+		{
+			name: "comments and blank lines",
+			src:  "# plan\n\n  \n10m Demo\n",
+			want: []agendaItem{{title: "Demo", planned: 10 * time.Minute}},
+		},
+		{
+			// Only the last field is the owner.
+			name: "at signs in the title",
+			src:  "1h Reply to @eve @ann\n",
+			want: []agendaItem{{title: "Reply to @eve", owner: "ann", planned: time.Hour}},
+		},
+		{
+			name: "no title",
+			src:  "5m @ann\n",
+			want: []agendaItem{{owner: "ann", planned: 5 * time.Minute}},
+		},
+		{name: "no items", src: "# nothing yet\n", wantErr: true},
+		{name: "empty", src: "", wantErr: true},
+		{name: "no time box", src: "Welcome @ann\n", wantErr: true},
+		{name: "empty time box", src: "0m Welcome\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseAgenda(strings.NewReader(tt.src))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseAgenda() error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseAgenda() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadAgenda(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "plan.txt")
+	if err := os.WriteFile(file, []byte("5m Welcome @ann\nsoon Roadmap\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// The error names the file, and the line.
+	_, err := loadAgenda(file)
+	if err == nil || !strings.Contains(err.Error(), file) || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("loadAgenda() error = %v, want one naming %s and line 2", err, file)
+	}
+	if _, err := loadAgenda(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("loadAgenda() of a missing file succeeded")
+	}
+}
+
+func TestFormatOffset(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "+00:00"},
+		{90 * time.Second, "+01:30"},
+		{-90 * time.Second, "-01:30"},
+		{1499 * time.Millisecond, "+00:01"},
+	}
+	for _, tt := range tests {
+		if got := formatOffset(tt.d); got != tt.want {
+			t.Errorf("formatOffset(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
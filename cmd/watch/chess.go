@@ -0,0 +1,354 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ValenTheRed/watch/internal/widget"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// The time controls of a chess clock, on top of the time each player
+// starts with.
+const (
+	// chessFischer adds the increment to the player's time after every
+	// move.
+	chessFischer = iota
+
+	// chessBronstein adds back the time the player used on the move,
+	// up to the delay, after every move.
+	chessBronstein
+
+	// chessDelay waits for the delay before the player's time starts to
+	// run on every move.
+	chessDelay
+)
+
+// chessControl is the time control of a chess clock, and it's increment
+// or delay.
+type chessControl struct {
+	mode  int
+	extra time.Duration
+}
+
+// Chess parses the arguments of the chess command, and returns a
+// function that sets up app as a chess clock. The only argument is the
+// time each player starts with.
+func Chess(args []string) (func(app *tview.Application) *tview.Application, error) {
+	var fischer, bronstein, delay durationFlag
+	players := playersFlag{"white", "black"}
+	fs := newFlagSet("chess")
+	fs.Var(&fischer, "increment", "")
+	fs.Var(&bronstein, "bronstein", "")
+	fs.Var(&delay, "delay", "")
+	fs.Var(&players, "players", "")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return nil, fmt.Errorf("chess takes the time of each player")
+	}
+	seconds, err := ParseDuration(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+	if seconds == 0 {
+		return nil, fmt.Errorf("chess: 0 not allowed; only positive integers")
+	}
+
+	var control chessControl
+	given := 0
+	for mode, extra := range []durationFlag{fischer, bronstein, delay} {
+		if extra > 0 {
+			control = chessControl{mode, time.Duration(extra) * time.Second}
+			given++
+		}
+	}
+	if given > 1 {
+		return nil, fmt.Errorf("chess: only one of -increment, -bronstein and -delay may be given")
+	}
+	return func(app *tview.Application) *tview.Application {
+		return ChessApp(app, players, seconds, control)
+	}, nil
+}
+
+// playersFlag is a flag.Value of the names of the players, given either
+// as their number, like 3, or their names separated by commas, like
+// "ann,bob,cat".
+type playersFlag []string
+
+func (p *playersFlag) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *playersFlag) Set(s string) error {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 2 {
+			return fmt.Errorf("must be at least 2 players")
+		}
+		*p = make(playersFlag, n)
+		for i := range *p {
+			(*p)[i] = fmt.Sprintf("player %d", i+1)
+		}
+		return nil
+	}
+	names := strings.Split(s, ",")
+	if len(names) < 2 {
+		return fmt.Errorf("must be at least 2 players")
+	}
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+	}
+	*p = names
+	return nil
+}
+
+// ChessApp returns app after setting the root to a chess clock of
+// players, each starting with seconds, and timed with control. Only the
+// clock of the player whose turn it is runs.
+func ChessApp(app *tview.Application, players []string, seconds int, control chessControl) *tview.Application {
+	clocks := make([]*widget.Clock, len(players))
+	titles := make([]*tview.TextView, len(players))
+	moves := make([]int, len(players))
+
+	var (
+		// turn is the player whose turn it is, or -1 before the first
+		// move.
+		turn = -1
+
+		// turnID changes with every turn, and pause, so that a delay
+		// started on an earlier one doesn't start the clock.
+		turnID = 0
+
+		// turnStart is the time left of the player at the start of
+		// the turn.
+		turnStart time.Duration
+
+		// flagged is the player whose time has run out, or -1.
+		flagged = -1
+
+		paused = false
+	)
+
+	status := tview.NewTextView()
+	status.SetTextAlign(tview.AlignCenter)
+
+	var refresh = func() {
+		for i, c := range clocks {
+			title := fmt.Sprintf("%s · %d moves", players[i], moves[i])
+			c.TextColor = ColorSurface
+			switch {
+			case i == flagged:
+				title += " · flag"
+				c.TextColor = ColorWorst
+			case i == turn:
+				title = "▶ " + title
+				c.TextColor = ColorForeground
+			}
+			titles[i].SetText(title)
+		}
+		switch {
+		case flagged != -1:
+			status.SetText(fmt.Sprintf("%s has run out of time", players[flagged]))
+		case paused:
+			status.SetText("paused")
+		case turn == -1:
+			status.SetText(fmt.Sprintf("%s to move; space starts the clock", players[0]))
+		default:
+			status.SetText(fmt.Sprintf("move %d", moves[0]+1))
+		}
+	}
+
+	for i := range players {
+		i := i
+		c := newClock(widget.NewTimer(seconds))
+		c.Changed = func() {
+			app.Draw()
+		}
+		c.SetDoneFunc(func() {
+			go app.QueueUpdateDraw(func() {
+				flagged = i
+				refresh()
+			})
+			playChime()
+		})
+		clocks[i] = c
+
+		titles[i] = tview.NewTextView()
+		titles[i].SetTextAlign(tview.AlignCenter)
+	}
+	// Increments can take a clock past seconds, so keep the fields of
+	// the most time any clock has.
+	nextFormat := formatCycle(func() int {
+		longest := seconds
+		for _, c := range clocks {
+			if v := int(c.Value() / time.Second); v > longest {
+				longest = v
+			}
+		}
+		return longest
+	}, nil, clocks...)
+	nextFormat()
+
+	// startTurn starts the turn of player i.
+	var startTurn = func(i int) {
+		turn = i
+		turnID++
+		turnStart = clocks[i].Value()
+		if control.mode == chessDelay && control.extra > 0 {
+			id := turnID
+			time.AfterFunc(control.extra, func() {
+				app.QueueUpdateDraw(func() {
+					if id == turnID {
+						clocks[i].Start()
+					}
+				})
+			})
+		} else {
+			clocks[i].Start()
+		}
+		refresh()
+	}
+
+	type info struct {
+		km     widget.KeyMap
+		action func()
+	}
+	interactions := struct {
+		move, pause, restart, format, quit info
+	}{
+		move: info{
+			km: widget.KeyMap{Key: "space", Desc: "end move"},
+		},
+		pause: info{
+			km: widget.KeyMap{Key: "p", Desc: "pause"},
+		},
+		restart: info{
+			km: widget.KeyMap{Key: "r", Desc: "restart"},
+		},
+		format: info{
+			km: widget.KeyMap{Key: "f", Desc: "format"},
+		},
+		quit: info{
+			km: widget.KeyMap{Key: "q", Desc: "quit"},
+		},
+	}
+
+	interactions.move.action = func() {
+		if flagged != -1 || paused {
+			return
+		}
+		if turn == -1 {
+			startTurn(0)
+			return
+		}
+		c := clocks[turn]
+		c.Stop()
+		moves[turn]++
+		switch control.mode {
+		case chessFischer:
+			c.Rewind(control.extra)
+		case chessBronstein:
+			used := turnStart - c.Value()
+			if used > control.extra {
+				used = control.extra
+			}
+			c.Rewind(used)
+		}
+		startTurn((turn + 1) % len(clocks))
+	}
+	interactions.pause.action = func() {
+		if turn == -1 || flagged != -1 {
+			return
+		}
+		paused = !paused
+		if paused {
+			turnID++
+			clocks[turn].Stop()
+		} else {
+			// The delay, if any, isn't waited for again.
+			clocks[turn].Start()
+		}
+		refresh()
+	}
+	interactions.restart.action = func() {
+		turnID++
+		for i, c := range clocks {
+			c.Stop()
+			c.SetElapsed(0)
+			moves[i] = 0
+		}
+		turn, flagged, paused = -1, -1, false
+		refresh()
+	}
+	interactions.format.action = func() {
+		nextFormat()
+	}
+	interactions.quit.action = func() {
+		app.Stop()
+	}
+
+	hv := widget.NewHelpView([]widget.KeyMap{
+		interactions.move.km, interactions.pause.km,
+		interactions.restart.km, interactions.format.km,
+		interactions.quit.km,
+	})
+	hv.SetDynamicColors(true)
+	hv.SetTextAlign(tview.AlignCenter)
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case ' ':
+				interactions.move.action()
+				return nil
+			case 'p':
+				interactions.pause.action()
+				return nil
+			case 'r':
+				interactions.restart.action()
+				return nil
+			case 'f':
+				interactions.format.action()
+				return nil
+			case 'q':
+				interactions.quit.action()
+				return nil
+			}
+		}
+		return event
+	})
+
+	board := tview.NewFlex()
+	for i, c := range clocks {
+		c.SetBorderPadding(1, 1, 2, 2)
+		board.AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(titles[i], 1, 0, false).
+			AddItem(c, 0, 1, false), 0, 1, false)
+	}
+
+	root := tview.NewFlex().SetDirection(tview.FlexRow)
+	root.AddItem(board, 0, 1, false)
+	root.AddItem(status, 1, 0, false)
+	root.AddItem(hv, 2, 1, false)
+
+	SetTheme = func() {
+		for i, c := range clocks {
+			c.SetBackgroundColor(ColorBackground)
+			c.ShadowColor = ColorShadow
+			titles[i].SetBackgroundColor(ColorBackground)
+			titles[i].SetTextColor(ColorSecondary)
+		}
+		status.SetBackgroundColor(ColorBackground)
+		status.SetTextColor(ColorSecondary)
+		hv.SetBackgroundColor(ColorBackground)
+		hv.SetKeyStyle(tcell.StyleDefault.Foreground(ColorSurface))
+		hv.SetDescStyle(tcell.StyleDefault.Foreground(ColorBorder))
+		hv.SetSeparatorStyle(tcell.StyleDefault.Foreground(ColorBorder))
+		refresh()
+	}
+
+	return app.SetRoot(root, true)
+}
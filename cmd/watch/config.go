@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// configPath returns the path of the config file, which is
+// watch/config inside of the user's config directory.
+func configPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "watch", "config")
+}
+
+// loadConfig sets the flags found in the config file at path. Each line
+// of the file is the name of a flag and it's value, separated by '=',
+// like so,
+//
+//	font = ansi-regular
+//	format = %H:%M:%S
+//
+// Blank lines and lines starting with '#' are ignored. A missing config
+// file is not an error.
+func loadConfig(path string) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("config: %v", err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.IndexByte(line, '=')
+		if i == -1 {
+			return fmt.Errorf("config: %s:%d: expected name = value", path, n)
+		}
+		name := strings.TrimSpace(line[:i])
+		value := strings.TrimSpace(line[i+1:])
+		if err := flag.Set(name, value); err != nil {
+			return fmt.Errorf("config: %s:%d: %v", path, n, err)
+		}
+	}
+	return sc.Err()
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ValenTheRed/watch/internal/widget"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Every parses the arguments of the every command, and returns a
+// function that sets up app to remind every period given in args.
+func Every(args []string) (func(app *tview.Application) *tview.Application, error) {
+	var breakFor durationFlag
+	label := "reminder"
+	fs := newFlagSet("every")
+	fs.Var(&breakFor, "for", "")
+	fs.StringVar(&label, "label", label, "")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return nil, fmt.Errorf("every takes the period of the reminder")
+	}
+	period, err := ParseDuration(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+	if period == 0 {
+		return nil, fmt.Errorf("every: 0 not allowed; only positive integers")
+	}
+	return func(app *tview.Application) *tview.Application {
+		return EveryApp(app, period, int(breakFor), label)
+	}, nil
+}
+
+// EveryApp returns app after setting the root to remind with label every
+// period seconds, one period after the other. With breakFor, a break of
+// breakFor seconds is counted down after every reminder, before the next
+// period starts.
+func EveryApp(app *tview.Application, period, breakFor int, label string) *tview.Application {
+	t := newClock(widget.NewTimer(period))
+	nextFormat := formatCycle(t.TotalSeconds, nil, t)
+	nextFormat()
+	t.Changed = func() {
+		app.Draw()
+	}
+
+	var (
+		// fired is the count of the reminders so far, and acknowledged
+		// the count of those acknowledged.
+		fired, acknowledged int
+
+		// pending is whether the last reminder is yet to be
+		// acknowledged.
+		pending bool
+
+		// onBreak is whether the break is being counted down.
+		onBreak bool
+	)
+
+	status := tview.NewTextView()
+	status.SetTextAlign(tview.AlignCenter)
+	var setStatus = func() {
+		text := "next " + label
+		switch {
+		case onBreak:
+			text = label
+		case pending:
+			text = label + " (a to acknowledge)"
+		}
+		status.SetText(fmt.Sprintf("%s · fired %d · acknowledged %d", text, fired, acknowledged))
+		if pending || onBreak {
+			status.SetTextColor(ColorPrimary)
+		} else {
+			status.SetTextColor(ColorSecondary)
+		}
+	}
+
+	// startPeriod starts the next period, or the break.
+	var startPeriod = func(duration int) {
+		t.SetTotalDuration(duration)
+		t.Restart()
+		setStatus()
+	}
+	t.SetDoneFunc(func() {
+		playChime()
+		go app.QueueUpdateDraw(func() {
+			if onBreak {
+				onBreak = false
+				startPeriod(period)
+				return
+			}
+			fired++
+			pending = true
+			if breakFor > 0 {
+				onBreak = true
+				startPeriod(breakFor)
+				return
+			}
+			startPeriod(period)
+		})
+	})
+
+	type info struct {
+		km     widget.KeyMap
+		action func()
+	}
+	interactions := struct {
+		acknowledge, playpause, restart, format, quit info
+	}{
+		acknowledge: info{
+			km: widget.KeyMap{Key: "a", Desc: "acknowledge"},
+		},
+		playpause: info{
+			km: widget.KeyMap{Key: "space", Desc: "play/pause"},
+		},
+		restart: info{
+			km: widget.KeyMap{Key: "r", Desc: "restart period"},
+		},
+		format: info{
+			km: widget.KeyMap{Key: "f", Desc: "format"},
+		},
+		quit: info{
+			km: widget.KeyMap{Key: "q", Desc: "quit"},
+		},
+	}
+
+	interactions.acknowledge.action = func() {
+		if !pending {
+			return
+		}
+		pending = false
+		acknowledged++
+		setStatus()
+	}
+	interactions.playpause.action = func() {
+		if t.Running() {
+			t.Stop()
+		} else {
+			t.Start()
+		}
+	}
+	interactions.restart.action = func() {
+		onBreak = false
+		startPeriod(period)
+	}
+	interactions.format.action = func() {
+		nextFormat()
+	}
+	interactions.quit.action = func() {
+		app.Stop()
+	}
+
+	hv := widget.NewHelpView([]widget.KeyMap{
+		interactions.acknowledge.km, interactions.playpause.km,
+		interactions.restart.km, interactions.format.km,
+		interactions.quit.km,
+	})
+	hv.SetDynamicColors(true)
+	hv.SetTextAlign(tview.AlignCenter)
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'a':
+				interactions.acknowledge.action()
+				return nil
+			case ' ':
+				interactions.playpause.action()
+				return nil
+			case 'r':
+				interactions.restart.action()
+				return nil
+			case 'f':
+				interactions.format.action()
+				return nil
+			case 'q':
+				interactions.quit.action()
+				return nil
+			}
+		}
+		return event
+	})
+
+	// It is meant to run all day in a small pane, so nothing but the
+	// clock grows.
+	root := tview.NewFlex().SetDirection(tview.FlexRow)
+	root.AddItem(t, 0, 1, false)
+	root.AddItem(status, 1, 0, false)
+	root.AddItem(hv, 1, 0, false)
+
+	t.SetVerticalAlign(widget.AlignCenter)
+
+	SetTheme = func() {
+		t.SetBackgroundColor(ColorBackground)
+		status.SetBackgroundColor(ColorBackground)
+		hv.SetBackgroundColor(ColorBackground)
+		t.TextColor = ColorForeground
+		t.ShadowColor = ColorShadow
+		hv.SetKeyStyle(tcell.StyleDefault.Foreground(ColorSurface))
+		hv.SetDescStyle(tcell.StyleDefault.Foreground(ColorBorder))
+		hv.SetSeparatorStyle(tcell.StyleDefault.Foreground(ColorBorder))
+		setStatus()
+	}
+
+	t.Start()
+	return app.SetRoot(root, true)
+}
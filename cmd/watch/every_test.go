@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+func TestEveryRestartAsDone(t *testing.T) {
+	app := EveryApp(tview.NewApplication(), 1, 0, "reminder")
+	runApp(t, app)
+	pressAfter(t, app, 1500*time.Millisecond, 'r')
+}
+
+// TestEveryPeriods runs every past more than one period, each of which
+// restarts the clock from it's done function.
+func TestEveryPeriods(t *testing.T) {
+	app := EveryApp(tview.NewApplication(), 1, 1, "reminder")
+	runApp(t, app)
+	time.Sleep(2500 * time.Millisecond)
+	pressAfter(t, app, 0, 'a')
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ValenTheRed/watch/internal/widget"
+	"github.com/rivo/tview"
+)
+
+var (
+	// lapsFormat is the format the laps are copied and written in.
+	lapsFormat lapsFormatFlag
+
+	// lapsOut is the file the laps are written to, in place of the
+	// clipboard, if set.
+	lapsOut string
+
+	// lapsTime shows the wall clock time of the laps in the lap table.
+	lapsTime bool
+
+	// autoLap is the interval, in seconds, at which laps are taken
+	// automatically, if set.
+	autoLap durationFlag
+
+	// lapDistance is the distance of a lap, in metres, if set.
+	lapDistance distanceFlag
+
+	// rest is the rest, in seconds, counted down after every lap, if
+	// set. The laps are then sets of an exercise.
+	rest durationFlag
+)
+
+// lapFlags registers the flags of the stopwatch's laps on fs.
+func lapFlags(fs *flag.FlagSet) {
+	fs.Var(&lapsFormat, "laps-format", "")
+	fs.StringVar(&lapsOut, "laps-out", lapsOut, "")
+	fs.BoolVar(&lapsTime, "laps-time", lapsTime, "")
+	fs.Var(&autoLap, "auto-lap", "")
+	fs.Var(&lapDistance, "lap-distance", "")
+	fs.Var(&rest, "rest", "")
+}
+
+// StopwatchCommand parses the arguments of the stopwatch command, which
+// is the same as running watch without a duration, but takes the flags
+// of the laps after it.
+func StopwatchCommand(args []string) (func(app *tview.Application) *tview.Application, error) {
+	fs := newFlagSet("stopwatch")
+	lapFlags(fs)
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("stopwatch takes no arguments")
+	}
+	return Stopwatch, nil
+}
+
+// durationFlag is a flag.Value of a duration in seconds, in any of the
+// formats of ParseDuration.
+type durationFlag int
+
+func (d *durationFlag) String() string {
+	return strconv.Itoa(int(*d))
+}
+
+func (d *durationFlag) Set(s string) error {
+	sec, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = durationFlag(sec)
+	return nil
+}
+
+// distanceUnits are the units of a distanceFlag, in metres.
+var distanceUnits = map[string]float64{
+	"":   1,
+	"m":  1,
+	"km": 1000,
+	"mi": 1609.344,
+	"yd": 0.9144,
+}
+
+// distanceFlag is a flag.Value of a distance in metres, given like 400m,
+// 1.5km, 1mi or 100yd. A distance without a unit is in metres.
+type distanceFlag float64
+
+func (d *distanceFlag) String() string {
+	return strconv.FormatFloat(float64(*d), 'f', -1, 64) + "m"
+}
+
+func (d *distanceFlag) Set(s string) error {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return r != '.' && (r < '0' || r > '9')
+	})
+	if i == -1 {
+		i = len(s)
+	}
+	n, err := strconv.ParseFloat(s[:i], 64)
+	unit, ok := distanceUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if err != nil || !ok || n <= 0 {
+		return fmt.Errorf("must be a distance like 400m, 1.5km, 1mi or 100yd")
+	}
+	*d = distanceFlag(n * unit)
+	return nil
+}
+
+// runEvent is the stopwatch starting, pausing, resuming or restarting,
+// logged along with the laps.
+type runEvent struct {
+	// Kind is one of start, pause, resume or restart.
+	Kind string
+
+	// At is the wall clock time of the event, and Elapsed the time on
+	// the stopwatch, before a restart.
+	At      time.Time
+	Elapsed int
+}
+
+// runLog is the log of the events of the stopwatch.
+type runLog []runEvent
+
+// add logs the event kind at elapsed seconds.
+func (l *runLog) add(kind string, elapsed int) {
+	*l = append(*l, runEvent{kind, time.Now(), elapsed})
+}
+
+// lapWriters write laps, and the log of the stopwatch, in each of the
+// formats of -laps-format.
+var lapWriters = map[string]func(w io.Writer, laps []widget.Lap, log runLog) error{
+	"text":     writeLapsText,
+	"csv":      writeLapsCSV,
+	"tsv":      writeLapsTSV,
+	"json":     writeLapsJSON,
+	"markdown": writeLapsMarkdown,
+}
+
+// lapsFormatFlag is a flag.Value of the name of one of lapWriters.
+type lapsFormatFlag string
+
+func (f *lapsFormatFlag) String() string {
+	return string(*f)
+}
+
+func (f *lapsFormatFlag) Set(s string) error {
+	if _, ok := lapWriters[s]; !ok {
+		return fmt.Errorf("must be text, csv, tsv, json or markdown")
+	}
+	*f = lapsFormatFlag(s)
+	return nil
+}
+
+// format returns the format of the laps. Unless set, it is guessed
+// from the extension of -laps-out, and is text otherwise.
+func (f lapsFormatFlag) format() string {
+	if f != "" {
+		return string(f)
+	}
+	switch strings.ToLower(filepath.Ext(lapsOut)) {
+	case ".csv":
+		return "csv"
+	case ".tsv":
+		return "tsv"
+	case ".json":
+		return "json"
+	case ".md", ".markdown":
+		return "markdown"
+	}
+	return "text"
+}
+
+// exportLaps returns laps, and log, in the format of -laps-format.
+func exportLaps(laps []widget.Lap, log runLog) []byte {
+	return writeToBytes(func(w io.Writer) error {
+		return lapWriters[lapsFormat.format()](w, laps, log)
+	})
+}
+
+// writeLapsFile writes laps, and log, to -laps-out.
+func writeLapsFile(laps []widget.Lap, log runLog) error {
+	if err := os.WriteFile(lapsOut, exportLaps(laps, log), 0o644); err != nil {
+		return fmt.Errorf("laps: %v", err)
+	}
+	return nil
+}
+
+// lapTimeLayout is the layout of the wall clock time of the laps.
+const lapTimeLayout = time.RFC3339
+
+// runRow is a row of the timeline, either a lap or an event.
+type runRow struct {
+	Event   string
+	At      time.Time
+	Lap     *widget.Lap
+	Elapsed int
+}
+
+// timeline returns the laps and the events of log as rows of lap records
+// and events in the order they happened. The row of a lap has the
+// event "lap".
+func timeline(laps []widget.Lap, log runLog) []runRow {
+	rows := make([]runRow, 0, len(laps)+len(log))
+	i, j := 0, 0
+	for i < len(laps) || j < len(log) {
+		if j == len(log) || (i < len(laps) && !log[j].At.Before(laps[i].At)) {
+			rows = append(rows, runRow{Event: "lap", At: laps[i].At, Lap: &laps[i]})
+			i++
+		} else {
+			rows = append(rows, runRow{Event: log[j].Kind, At: log[j].At, Elapsed: log[j].Elapsed})
+			j++
+		}
+	}
+	return rows
+}
+
+func writeLapsText(w io.Writer, laps []widget.Lap, log runLog) error {
+	for _, row := range timeline(laps, log) {
+		var line string
+		if lap := row.Lap; lap != nil {
+			line = fmt.Sprintf("%s lap %2d %s %s", row.At.Format("15:04:05"), lap.Number,
+				widget.SecondWithColons(lap.Seconds),
+				widget.SecondWithColons(lap.Total))
+			if lap.Rest > 0 {
+				line += " rest " + widget.SecondWithColons(lap.Rest)
+			}
+			if lap.Note != "" {
+				line += " " + lap.Note
+			}
+		} else {
+			line = fmt.Sprintf("%s %s at %s", row.At.Format("15:04:05"), row.Event,
+				widget.SecondWithColons(row.Elapsed))
+		}
+		_, err := fmt.Fprintln(w, line)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lapRecords returns laps and the events of log as records, with a
+// header record. Events only have the total time, that is, the time on
+// the stopwatch.
+func lapRecords(laps []widget.Lap, log runLog) [][]string {
+	records := [][]string{{"event", "lap", "lap_seconds", "lap_time", "total_seconds", "total_time", "rest_seconds", "at", "note"}}
+	for _, row := range timeline(laps, log) {
+		if lap := row.Lap; lap != nil {
+			records = append(records, []string{
+				row.Event,
+				fmt.Sprint(lap.Number),
+				fmt.Sprint(lap.Seconds),
+				widget.SecondWithColons(lap.Seconds),
+				fmt.Sprint(lap.Total),
+				widget.SecondWithColons(lap.Total),
+				fmt.Sprint(lap.Rest),
+				row.At.Format(lapTimeLayout),
+				lap.Note,
+			})
+			continue
+		}
+		records = append(records, []string{
+			row.Event, "", "", "",
+			fmt.Sprint(row.Elapsed),
+			widget.SecondWithColons(row.Elapsed),
+			"",
+			row.At.Format(lapTimeLayout),
+			"",
+		})
+	}
+	return records
+}
+
+func writeLapsCSV(w io.Writer, laps []widget.Lap, log runLog) error {
+	return csv.NewWriter(w).WriteAll(lapRecords(laps, log))
+}
+
+func writeLapsTSV(w io.Writer, laps []widget.Lap, log runLog) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = '\t'
+	return cw.WriteAll(lapRecords(laps, log))
+}
+
+func writeLapsJSON(w io.Writer, laps []widget.Lap, log runLog) error {
+	type lapRecord struct {
+		Lap          int    `json:"lap"`
+		LapSeconds   int    `json:"lap_seconds"`
+		TotalSeconds int    `json:"total_seconds"`
+		RestSeconds  int    `json:"rest_seconds,omitempty"`
+		At           string `json:"at"`
+		Note         string `json:"note,omitempty"`
+	}
+	type eventRecord struct {
+		Event        string `json:"event"`
+		TotalSeconds int    `json:"total_seconds"`
+		At           string `json:"at"`
+	}
+	record := struct {
+		Events []eventRecord `json:"events"`
+		Laps   []lapRecord   `json:"laps"`
+	}{
+		Events: make([]eventRecord, len(log)),
+		Laps:   make([]lapRecord, len(laps)),
+	}
+	for i, e := range log {
+		record.Events[i] = eventRecord{e.Kind, e.Elapsed, e.At.Format(lapTimeLayout)}
+	}
+	for i, lap := range laps {
+		record.Laps[i] = lapRecord{lap.Number, lap.Seconds, lap.Total, lap.Rest, lap.At.Format(lapTimeLayout), lap.Note}
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(record)
+}
+
+func writeLapsMarkdown(w io.Writer, laps []widget.Lap, log runLog) error {
+	rows := []string{
+		"| At | Event | Lap | Lap time | Total | Rest | Note |",
+		"| -- | ----- | --: | -------: | ----: | ---: | ---- |",
+	}
+	for _, row := range timeline(laps, log) {
+		at := row.At.Format("2006-01-02 15:04:05")
+		if lap := row.Lap; lap != nil {
+			// A pipe would end the cell early.
+			note := strings.ReplaceAll(lap.Note, "|", "\\|")
+			rest := ""
+			if lap.Rest > 0 {
+				rest = widget.SecondWithColons(lap.Rest)
+			}
+			rows = append(rows, fmt.Sprintf("| %s | lap | %d | %s | %s | %s | %s |", at, lap.Number,
+				widget.SecondWithColons(lap.Seconds),
+				widget.SecondWithColons(lap.Total), rest, note))
+			continue
+		}
+		rows = append(rows, fmt.Sprintf("| %s | %s | | | %s | | |", at, row.Event,
+			widget.SecondWithColons(row.Elapsed)))
+	}
+	_, err := io.WriteString(w, strings.Join(rows, "\n")+"\n")
+	return err
+}
@@ -5,11 +5,14 @@ import (
 	_ "embed"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"math"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ValenTheRed/watch/internal/widget"
@@ -23,23 +26,213 @@ import (
 )
 
 var (
-	usage = `usage: watch [-help] [duration]
+	usage = `usage: watch [-help] [flags] [duration]...
+       watch [-help] until [flags] date [time]
+       watch [-help] stopwatch [flags]
+       watch [-help] clock [flags]
+       watch [-help] splits [flags] file
+       watch [-help] race [flags] [file]
+       watch [-help] chess [flags] duration
+       watch [-help] agenda [flags] file
+       watch [-help] talk [flags] duration
+       watch [-help] every [flags] duration
 A clock with a stopwatch and a timer.
 
 Specify a duration to start a timer. Or, leave it alone to start a stopwatch.
 
+commands:
+stopwatch   start a stopwatch, same as leaving the duration alone
+until       count down to date and time, like 2026-12-31T23:59:59 or
+            "2026-11-03 09:00", and count up since it once it has passed
+clock       show the time of day
+splits      time a speedrun of the segments in file, either one name per
+            line or a LiveSplit (.lss) file, against the personal best
+race        record the finish of every bib typed in on one race clock,
+            flagging bibs that already finished or, with a file of the
+            entries, one "bib name" per line, that aren't entries;
+            -results-out writes the results as CSV to a file in place of
+            the clipboard
+chess       a chess clock of duration for each player; space ends a move.
+            -increment adds to the player's time after every move (Fischer),
+            -bronstein adds back the time used on the move, up to it, and
+            -delay waits before the player's time runs on every move.
+            -players is the number of players, or their names, like
+            "ann,bob" (default white,black)
+agenda      time the items of the agenda in file, one "duration title
+            @owner" per line, one after the other; an item that runs over,
+            or finishes early, carries over to the end of the meeting
+talk        time a talk of duration, with a speaker view of the time
+            elapsed, the time left and the progress, counting overtime
+            once the time runs out. -warn, given more than once, like
+            -warn 5m or -warn "1m=wrap up", changes the colors and chimes
+            as the time left crosses it (default 5m and 1m)
+every       remind every duration, like 20m, one period after the other,
+            counting how many reminders fired and how many were
+            acknowledged with the a key. -for counts down a break, like
+            20s, after every reminder, and -label names the reminder
+
 optional arguments:
-duration    supported formats - [[hh:]mm:]ss
--help	    display this help message and exit`
+duration    supported formats - [[[dd:]hh:]mm:]ss, or like 2d3h15m
+-font       font of the clock, either one of %s,
+            or the path to a FIGlet (.flf) font file (default ansi-shadow)
+-analog     draw the time of day on an analog dial, and the progress of
+            timers as a sweep of the dial in place of the progress bar
+-ring       draw the timer inside a ring that fills as it runs, in place of
+            the progress bar
+-bar        style of the progress bar, either one of box, thin, braille or
+            ascii (default box)
+-bar-label  label of the progress bar, either one of percent, elapsed,
+            remaining or end (the time the timer ends at)
+-bar-vertical
+            draw the progress bar vertically, beside the timer
+-compact    draw the clock in two or three rows, with half blocks (half) or
+            Braille patterns (braille)
+-tabular    pad every digit of the clock to the same width
+-fixed      keep the hour and minute fields of the clock even when zero
+-format     format of the clock and the table cells, like %%H:%%M:%%S; may be
+            given more than once to cycle through them with the f key.
+            verbs: %%D/%%d days, %%H/%%h hours, %%M/%%m minutes, %%S/%%s seconds,
+            %%f tenths of a second; upper case verbs are zero padded
+-laps-format
+            format the stopwatch's laps are copied in, either one of text,
+            csv, tsv, json or markdown (default from the extension of
+            -laps-out, or text)
+-laps-out   file the laps are written to, in place of the clipboard, when
+            copied and on quitting
+-laps-time  show the wall clock time of the laps
+-auto-lap   take a lap automatically every duration, like 1m
+-lap-distance
+            distance of a lap, like 400m, 1.5km or 1mi, to show the pace
+            and speed of the laps
+-rest       rest counted down after every lap, like 90s, with the laps
+            shown as sets of an exercise
+-hold       hold every item of the timer's queue, so that each waits,
+            paused, for the play key once the one before it is done; a
+            duration ending in '!', like 10m!, holds only it's item
+-help	    display this help message and exit
+
+Flags may also be set in %s, one "name = value" per line.`
+
+	// fontName is the name or path of the clock's font.
+	fontName = "ansi-shadow"
+
+	// font is the clock's font loaded from fontName.
+	font *widget.Font
+
+	// analog draws an analog dial.
+	analog bool
+
+	// compact is the compact rendering of the clock.
+	compact compactFlag
+
+	// ring draws the timer inside a progress ring.
+	ring bool
+
+	// barStyle is the style of the progress bar.
+	barStyle barStyleFlag
+
+	// barLabel is the label of the progress bar, if any.
+	barLabel barLabelFlag
+
+	// barVertical draws the progress bar vertically.
+	barVertical bool
+
+	// tabular pads the digits of the clock to the same width.
+	tabular bool
+
+	// fixed keeps the fields of the clock from being dropped.
+	fixed bool
+
+	// templates are the templates given with -format.
+	templates templateList
+
+	// hold holds every item of the timer's queue, so that each waits,
+	// paused, to be started once the queue advances to it.
+	hold bool
 )
 
+// commands maps the name of a command to a function that parses it's
+// arguments, and returns a function that sets up app for the command.
+var commands = map[string]func(args []string) (func(app *tview.Application) *tview.Application, error){
+	"until":     Until,
+	"clock":     TimeOfDay,
+	"splits":    Splits,
+	"race":      Race,
+	"chess":     Chess,
+	"agenda":    Agenda,
+	"talk":      Talk,
+	"every":     Every,
+	"stopwatch": StopwatchCommand,
+}
+
+// clockFlags registers the flags that set the look of the clock on fs.
+// They are shared by every command, and their current values are used
+// as the defaults, so that a command's flags add to those given before
+// the command.
+func clockFlags(fs *flag.FlagSet) {
+	fs.StringVar(&fontName, "font", fontName, "")
+	fs.BoolVar(&analog, "analog", analog, "")
+	fs.Var(&compact, "compact", "")
+	fs.BoolVar(&ring, "ring", ring, "")
+	fs.Var(&barStyle, "bar", "")
+	fs.Var(&barLabel, "bar-label", "")
+	fs.BoolVar(&barVertical, "bar-vertical", barVertical, "")
+	fs.BoolVar(&tabular, "tabular", tabular, "")
+	fs.BoolVar(&fixed, "fixed", fixed, "")
+	fs.Var(&templates, "format", "")
+}
+
+// newFlagSet returns a FlagSet, with the clock flags, for the command
+// name.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = flag.Usage
+	clockFlags(fs)
+	return fs
+}
+
 //go:embed "ping.flac"
 var pingFile []byte
 
+// chime is the ping file decoded by playChime.
+var chime struct {
+	once   sync.Once
+	buffer *beep.Buffer
+}
+
+// playChime plays the ping file, without waiting for it to end. The
+// speaker is initialised on the first call.
+func playChime() {
+	playChimeAt(1)
+}
+
+// playChimeAt plays the ping file like playChime, with it's pitch, and
+// speed, scaled by ratio, so that chimes can be told apart.
+func playChimeAt(ratio float64) {
+	chime.once.Do(func() {
+		// NOTE: error ignored
+		streamer, format, _ := flac.Decode(bytes.NewReader(pingFile))
+		defer streamer.Close()
+
+		speaker.Init(format.SampleRate, format.SampleRate.N(time.Second/10))
+
+		chime.buffer = beep.NewBuffer(format)
+		chime.buffer.Append(streamer)
+	})
+	var streamer beep.Streamer = chime.buffer.Streamer(0, chime.buffer.Len())
+	if ratio != 1 {
+		streamer = beep.ResampleRatio(4, ratio, streamer)
+	}
+	speaker.Play(streamer)
+}
+
 func init() {
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "%s\n", usage)
+		fmt.Fprintf(os.Stderr, usage+"\n", strings.Join(widget.FontNames(), ", "), configPath())
 	}
+	clockFlags(flag.CommandLine)
+	lapFlags(flag.CommandLine)
+	flag.BoolVar(&hold, "hold", hold, "")
 
 	tview.Borders.HorizontalFocus = tview.Borders.Horizontal
 	tview.Borders.VerticalFocus = tview.Borders.Vertical
@@ -47,10 +240,6 @@ func init() {
 	tview.Borders.TopRightFocus = tview.Borders.TopRight
 	tview.Borders.BottomLeftFocus = tview.Borders.BottomLeft
 	tview.Borders.BottomRightFocus = tview.Borders.BottomRight
-
-	if err := clipboard.Init(); err != nil {
-		panic(err)
-	}
 }
 
 var (
@@ -59,6 +248,10 @@ var (
 	// displayed.
 	SetTheme func()
 
+	// Cleanup, if set, will be called after the application stops, by
+	// any means, like to save what is left to be saved.
+	Cleanup func() error
+
 	ColorBackground = tcell.GetColor(colorful.Hcl(308.3, 0.02548, 0.04965).Hex())
 	ColorForeground = tcell.GetColor(colorful.Hcl(0, 0.0001262, 0.8941).Hex())
 	ColorPrimary    = tcell.GetColor(colorful.Hcl(15, .7, .5).Hex())
@@ -66,44 +259,91 @@ var (
 	ColorBorder     = tcell.GetColor(colorful.Hcl(0, 4.714e-05, 0.2262).Hex())
 	ColorSurface    = tcell.GetColor(colorful.Hcl(0, 6.055e-05, 0.336).Hex())
 	ColorShadow     = tcell.ColorGrey
+	ColorBest       = tcell.GetColor(colorful.Hcl(135, .5, .6).Hex())
+	ColorWorst      = tcell.GetColor(colorful.Hcl(15, .7, .6).Hex())
+	ColorGold       = tcell.GetColor(colorful.Hcl(85, .6, .75).Hex())
 )
 
 func main() {
+	if err := clipboard.Init(); err != nil {
+		panic(err)
+	}
+	if err := loadConfig(configPath()); err != nil {
+		log.Fatalln(fmt.Errorf("main: %v", err))
+	}
+	// Formats given on the command line replace those of the config.
+	templates.replace = true
 	flag.Parse()
 
-	durations := make([]int, len(flag.Args()))
-	for i := range durations {
+	var build func(app *tview.Application) *tview.Application
+	if command, ok := commands[flag.Arg(0)]; ok {
 		var err error
-		durations[i], err = ParseDuration(flag.Arg(i))
-		if err != nil {
+		if build, err = command(flag.Args()[1:]); err != nil {
 			log.Fatalln(fmt.Errorf("main: %v", err))
 		}
-		if durations[i] == 0 {
-			log.Fatalln(fmt.Errorf("main: 0 not allowed; only positive integers"))
+	} else {
+		durations := make([]int, len(flag.Args()))
+		holds := make([]bool, len(flag.Args()))
+		for i := range durations {
+			// A duration ending in '!' holds it's item.
+			arg := strings.TrimSuffix(flag.Arg(i), "!")
+			holds[i] = hold || arg != flag.Arg(i)
+			var err error
+			durations[i], err = ParseDuration(arg)
+			if err != nil {
+				log.Fatalln(fmt.Errorf("main: %v", err))
+			}
+			if durations[i] == 0 {
+				log.Fatalln(fmt.Errorf("main: 0 not allowed; only positive integers"))
+			}
+		}
+		build = func(app *tview.Application) *tview.Application {
+			if len(durations) == 0 {
+				return Stopwatch(app)
+			}
+			return Timer(app, durations, holds)
 		}
 	}
 
-	app := tview.NewApplication().EnableMouse(true)
-	if len(durations) == 0 {
-		app = Stopwatch(app)
-	} else {
-		app = Timer(app, durations)
+	var err error
+	if font, err = widget.LoadFont(fontName); err != nil {
+		log.Fatalln(fmt.Errorf("main: %v", err))
 	}
 
+	app := build(tview.NewApplication().EnableMouse(true))
+
 	SetTheme()
 	if err := app.Run(); err != nil {
 		panic(err)
 	}
+	if Cleanup != nil {
+		if err := Cleanup(); err != nil {
+			log.Fatalln(fmt.Errorf("main: %v", err))
+		}
+	}
 }
 
 // Stopwatch returns app after setting the root and starting the
 // stopwatch.
 func Stopwatch(app *tview.Application) *tview.Application {
-	s := widget.NewStopwatch()
+	s := newClock(widget.NewStopwatch())
+	l := widget.NewLapTable()
+	// lastAutoLap is the elapsed seconds the last lap was taken
+	// automatically at.
+	lastAutoLap := 0
 	s.Changed = func() {
-		app.Draw()
+		if autoLap == 0 {
+			app.Draw()
+			return
+		}
+		app.QueueUpdateDraw(func() {
+			e := s.ElapsedSeconds()
+			if e > 0 && e%int(autoLap) == 0 && e != lastAutoLap {
+				lastAutoLap = e
+				l.AddLap(e)
+			}
+		})
 	}
-	l := widget.NewLapTable()
 
 	type info struct {
 		km     widget.KeyMap
@@ -111,7 +351,7 @@ func Stopwatch(app *tview.Application) *tview.Application {
 		action func()
 	}
 	interactions := struct {
-		lap, playpause, restart, quit, copy info
+		lap, playpause, restart, quit, copy, format, delta, note, remove, undo, time info
 	}{
 		lap: info{
 			km:     widget.KeyMap{Key: "l", Desc: "lap"},
@@ -133,26 +373,166 @@ func Stopwatch(app *tview.Application) *tview.Application {
 			km:     widget.KeyMap{Key: "y/c", Desc: "copy laps"},
 			button: tview.NewButton(":: copy laps"),
 		},
+		format: info{
+			km:     widget.KeyMap{Key: "f", Desc: "format"},
+			button: nil,
+		},
+		delta: info{
+			km:     widget.KeyMap{Key: "d", Desc: "delta"},
+			button: nil,
+		},
+		note: info{
+			km:     widget.KeyMap{Key: "n", Desc: "note"},
+			button: nil,
+		},
+		remove: info{
+			km:     widget.KeyMap{Key: "x", Desc: "delete lap"},
+			button: nil,
+		},
+		undo: info{
+			km:     widget.KeyMap{Key: "u", Desc: "undo"},
+			button: nil,
+		},
+		time: info{
+			km:     widget.KeyMap{Key: "t", Desc: "time of day"},
+			button: nil,
+		},
+	}
+	l.SetShowTime(lapsTime)
+	l.SetDistance(float64(lapDistance))
+	l.SetShowSets(rest > 0)
+
+	// r counts down the rest after every lap, with -rest, and resting
+	// is the time the lap the rest follows was taken at.
+	r := newClock(widget.NewTimer(int(rest)))
+	var resting time.Time
+	// setRest sets the rest after the lap taken at resting, unless it
+	// has since been deleted, or undone.
+	var setRest = func() {
+		if row := l.Row(resting); row != -1 {
+			l.SetRest(row, r.ElapsedSeconds())
+		}
 	}
+	r.Changed = func() {
+		app.Draw()
+	}
+	r.SetDoneFunc(func() {
+		go app.QueueUpdateDraw(setRest)
+		playChime()
+	})
+
+	// events is the log of the stopwatch starting, pausing, resuming and
+	// restarting, exported along with the laps.
+	var events runLog
+	// restarting is set while the stopwatch restarts, so that it is
+	// logged as a restart rather than a pause and a resume.
+	restarting := false
 
+	// Stopwatch has no end, so keep it's hours with -fixed.
+	formats := clockFormats(s, func() int { return 3600 })
+	format := 0
+	l.SetFormat(formats[format]())
+
+	Cleanup = func() error {
+		if lapsOut == "" || l.GetRowCount() == 0 {
+			return nil
+		}
+		return writeLapsFile(l.Laps(), events)
+	}
+
+	// status reports the laps being copied, or written, and why they
+	// couldn't be.
+	status := tview.NewTextView()
+	status.SetTextAlign(tview.AlignCenter)
 	interactions.copy.action = func() {
-		var lines []byte
-		for row := l.GetRowCount() - 1; row > -1; row-- {
-			lap, time, overall := l.GetLap(row)
-			lines = append(lines, []byte(fmt.Sprintf("%2d", lap))...)
-			lines = append(lines, ' ')
-			lines = append(lines, []byte(widget.SecondWithColons(time))...)
-			lines = append(lines, ' ')
-			lines = append(lines, []byte(widget.SecondWithColons(overall))...)
-			lines = append(lines, '\n')
-		}
-		clipboard.Write(clipboard.FmtText, lines)
+		if lapsOut != "" {
+			if err := writeLapsFile(l.Laps(), events); err != nil {
+				status.SetText(err.Error())
+				return
+			}
+			status.SetText("laps written to " + lapsOut)
+			return
+		}
+		clipboard.Write(clipboard.FmtText, exportLaps(l.Laps(), events))
+		status.SetText("laps copied")
 	}
 	interactions.lap.action = func() {
+		if rest == 0 {
+			l.AddLap(s.ElapsedSeconds())
+			return
+		}
+		// A lap during the rest cuts it short, and starts the next set.
+		if r.Running() {
+			r.Stop()
+			setRest()
+			return
+		}
 		l.AddLap(s.ElapsedSeconds())
+		laps := l.Laps()
+		resting = laps[len(laps)-1].At
+		r.Restart()
+	}
+	interactions.format.action = func() {
+		format = (format + 1) % len(formats)
+		l.SetFormat(formats[format]())
+	}
+	// The note of the highlighted lap is typed into a box over the
+	// stopwatch.
+	pages := tview.NewPages()
+	note := tview.NewInputField()
+	note.SetBorder(true)
+	noteBox := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(note, 3, 0, true).
+			AddItem(nil, 0, 1, false), 50, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	interactions.note.action = func() {
+		row := l.HighlightedRow()
+		if row == -1 {
+			return
+		}
+		laps := l.Laps()
+		lap := laps[len(laps)-1-row]
+		note.SetTitle(fmt.Sprintf(" lap %d note ", lap.Number))
+		note.SetText(lap.Note)
+		note.SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEnter {
+				l.SetNote(row, note.GetText())
+			}
+			pages.HidePage("note")
+			app.SetFocus(l)
+		})
+		pages.ShowPage("note")
+		app.SetFocus(note)
+	}
+	interactions.remove.action = func() {
+		if row := l.HighlightedRow(); row != -1 {
+			l.DeleteLap(row)
+		}
+	}
+	interactions.undo.action = func() {
+		l.Undo()
+	}
+	interactions.delta.action = func() {
+		if l.DeltaMode() == widget.DeltaPrevious {
+			l.SetDeltaMode(widget.DeltaMean)
+		} else {
+			l.SetDeltaMode(widget.DeltaPrevious)
+		}
+	}
+	interactions.time.action = func() {
+		l.SetShowTime(!l.ShowTime())
 	}
 	interactions.restart.action = func() {
+		events.add("restart", s.ElapsedSeconds())
+		lastAutoLap = 0
+		r.Stop()
+		restarting = true
 		s.Restart()
+		restarting = false
 	}
 	interactions.playpause.action = func() {
 		if s.Running() {
@@ -201,9 +581,18 @@ func Stopwatch(app *tview.Application) *tview.Application {
 
 	s.Started = func() {
 		interactions.playpause.button.SetLabel("❚❚ pause")
+		switch {
+		case len(events) == 0:
+			events.add("start", s.ElapsedSeconds())
+		case !restarting:
+			events.add("resume", s.ElapsedSeconds())
+		}
 	}
 	s.Stopped = func() {
 		interactions.playpause.button.SetLabel("▶ play")
+		if !restarting {
+			events.add("pause", s.ElapsedSeconds())
+		}
 	}
 
 	bc := widget.NewButtonColumn([]*tview.Button{
@@ -214,11 +603,17 @@ func Stopwatch(app *tview.Application) *tview.Application {
 	hv := widget.NewHelpView([]widget.KeyMap{
 		interactions.lap.km, interactions.playpause.km,
 		interactions.restart.km, interactions.quit.km, interactions.copy.km,
+		interactions.format.km, interactions.delta.km, interactions.note.km,
+		interactions.remove.km, interactions.undo.km, interactions.time.km,
 	})
 	hv.SetDynamicColors(true)
 	hv.SetTextAlign(tview.AlignCenter)
 
 	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Leave the keys to the note while it is typed.
+		if name, _ := pages.GetFrontPage(); name == "note" {
+			return event
+		}
 		switch event.Key() {
 		case tcell.KeyRune:
 			switch event.Rune() {
@@ -237,6 +632,24 @@ func Stopwatch(app *tview.Application) *tview.Application {
 			case 'y', 'c':
 				interactions.copy.action()
 				return nil
+			case 'f':
+				interactions.format.action()
+				return nil
+			case 'd':
+				interactions.delta.action()
+				return nil
+			case 'n':
+				interactions.note.action()
+				return nil
+			case 'x':
+				interactions.remove.action()
+				return nil
+			case 'u':
+				interactions.undo.action()
+				return nil
+			case 't':
+				interactions.time.action()
+				return nil
 			}
 		}
 		return event
@@ -244,9 +657,14 @@ func Stopwatch(app *tview.Application) *tview.Application {
 
 	f := tview.NewFlex().SetDirection(tview.FlexRow)
 	f.AddItem(s, 0, 1, false)
+	if rest > 0 {
+		f.AddItem(r, 0, 1, false)
+	}
 	f.AddItem(bc, 0, 1, false)
+	f.AddItem(status, 1, 0, false)
 	f.AddItem(hv, 2, 1, false)
 
+	r.SetVerticalAlign(widget.AlignCenter)
 	s.SetVerticalAlign(widget.AlignDown)
 	s.SetBorderPadding(1, 1, 2, 2)
 	bc.SetVerticalAlign(widget.AlignUp)
@@ -264,11 +682,24 @@ func Stopwatch(app *tview.Application) *tview.Application {
 		l.SetHeaderStyle(tcell.StyleDefault.Foreground(ColorForeground))
 		l.SetUnderlineStyle(tcell.StyleDefault.Foreground(ColorSecondary))
 		l.SetCellStyle(tcell.StyleDefault.Foreground(ColorForeground))
+		l.SetBestStyle(tcell.StyleDefault.Foreground(ColorBest))
+		l.SetWorstStyle(tcell.StyleDefault.Foreground(ColorWorst))
+		l.SetFooterStyle(tcell.StyleDefault.Foreground(ColorSecondary))
+		note.SetBackgroundColor(ColorBackground)
+		note.SetBorderColor(ColorSecondary)
+		note.SetTitleColor(ColorForeground)
+		note.SetFieldBackgroundColor(ColorSurface)
+		note.SetFieldTextColor(ColorForeground)
 		s.SetBackgroundColor(ColorBackground)
 		bc.SetBackgroundColor(ColorBackground)
+		status.SetBackgroundColor(ColorBackground)
+		status.SetTextColor(ColorSecondary)
 		hv.SetBackgroundColor(ColorBackground)
 		s.TextColor = ColorForeground
 		s.ShadowColor = ColorShadow
+		r.SetBackgroundColor(ColorBackground)
+		r.TextColor = ColorSecondary
+		r.ShadowColor = ColorShadow
 		hv.SetKeyStyle(tcell.StyleDefault.Foreground(ColorSurface))
 		hv.SetDescStyle(tcell.StyleDefault.Foreground(ColorBorder))
 		hv.SetSeparatorStyle(tcell.StyleDefault.Foreground(ColorBorder))
@@ -285,48 +716,139 @@ func Stopwatch(app *tview.Application) *tview.Application {
 	}
 
 	s.Start()
-	return app.SetRoot(root, true)
+	pages.AddPage("stopwatch", root, true, true)
+	pages.AddPage("note", noteBox, true, false)
+
+	return app.SetRoot(pages, true)
 }
 
-// Timer returns app after setting the root and starting the timer.
-func Timer(app *tview.Application, durations []int) *tview.Application {
-	t := widget.NewTimer(durations[0])
+// Timer returns app after setting the root and starting the timer. The
+// items of the queue that holds marks wait, paused, to be started once
+// the queue advances to them, the first one included.
+func Timer(app *tview.Application, durations []int, holds []bool) *tview.Application {
+	t := newClock(widget.NewTimer(durations[0]))
 	p := widget.NewProgressBar()
+	p.SetStyle(int(barStyle))
+	p.SetVertical(barVertical)
+	p.Label = barLabel.label(p, t)
 
-	t.Changed = func() {
-		p.SetPercent(t.ElapsedSeconds() * 100 / t.TotalSeconds())
-		app.Draw()
-	}
+	// With -analog, the dial's sweep shows the progress in place of
+	// the progress bar.
+	a := widget.NewAnalogClock().SetSweep(0)
+
+	// With -ring, the timer is drawn inside of the ring instead.
+	r := widget.NewRing().SetCenter(t)
 
 	q := widget.NewQueue(durations...)
+	for i, h := range holds {
+		q.SetHold(i, h)
+	}
+
+	// advancing is whether the queue is advancing to the next item on
+	// it's own, as the current one is done, which a held item waits on.
+	advancing := false
+
+	// The progress of the whole queue, with a marker at the boundary of
+	// every item.
+	total := widget.NewProgressBar()
+	total.SetStyle(int(barStyle))
+	// current returns the time left of the current item.
+	var current = func() time.Duration {
+		if v := t.Value(); v > 0 {
+			return v
+		}
+		return 0
+	}
+	var remaining = func() time.Duration {
+		d := q.Durations()
+		v := current()
+		for i := q.Head() + 1; i < len(d); i++ {
+			if q.Status(i) == widget.ItemPending {
+				v += time.Duration(d[i]) * time.Second
+			}
+		}
+		return v
+	}
+	var setTotal = func() {
+		d := q.Durations()
+		sum := 0
+		for _, duration := range d {
+			sum += duration
+		}
+		markers := make([]float64, len(d)-1)
+		passed := 0
+		for i := range markers {
+			passed += d[i]
+			markers[i] = float64(passed) / float64(sum)
+		}
+		total.SetMarkers(markers...)
+		progress := 1 - float64(remaining())/float64(time.Duration(sum)*time.Second)
+		total.SetProgress(math.Max(0, math.Min(1, progress)))
+	}
+	total.Label = func() string {
+		v := remaining()
+		return fmt.Sprintf("%s left · ends at %s",
+			widget.SecondWithColons(int(math.Ceil(v.Seconds()))),
+			time.Now().Add(v).Format("15:04"))
+	}
+	setTotal()
+	q.SetEndTimes(current())
+
 	q.SetSelectedFunc(func(row int) {
-		duration := q.GetCell(row, 1).GetReference().(int)
+		duration := q.Durations()[row]
 		t.SetTotalDuration(duration)
-		t.Restart()
+		if advancing && q.Hold(row) {
+			// The clock stopped on it's own as the item before was
+			// done, so it's only set back, and shown as paused.
+			t.SetElapsed(0)
+			t.Stopped()
+		} else {
+			t.Restart()
+		}
+		setTotal()
+		q.SetEndTimes(current())
 	})
-	t.SetDoneFunc(func() func() {
-		// Init ping file
-		// NOTE: error ignored
-		streamer, format, _ := flac.Decode(bytes.NewReader(pingFile))
-		defer streamer.Close()
-
-		speaker.Init(format.SampleRate, format.SampleRate.N(time.Second/10))
 
-		buffer := beep.NewBuffer(format)
-		buffer.Append(streamer)
-		return func() {
-			// BUG: if the current timer is the last one in the queue,
-			// then a stream of more than one second leads to a race
-			// condition where the timer ticks one extra second. This
-			// causes panic as there is no character for negative sign
-			// in the ANSIShadow font map.
-			speaker.Play(buffer.Streamer(0, buffer.Len()))
-			// In lieu of above bug, don't wait for the stream to, just
-			// in case it turns out to be longer than one seoncd.
-			<-time.After(800 * time.Millisecond)
-			q.Next()
-		}
-	}())
+	t.Changed = func() {
+		app.QueueUpdateDraw(func() {
+			progress := 1 - float64(t.Value())/float64(time.Duration(t.TotalSeconds())*time.Second)
+			progress = math.Max(0, math.Min(1, progress))
+			p.SetProgress(progress)
+			a.SetSweep(progress)
+			r.SetProgress(progress)
+			setTotal()
+			q.SetEndTimes(current())
+		})
+	}
+	// Nothing changes while the timer is paused, yet the end times move
+	// on with the time of day.
+	go widget.WorkerEvery(time.Minute, func() {
+		app.QueueUpdateDraw(func() {
+			if !t.Running() {
+				setTotal()
+				q.SetEndTimes(current())
+			}
+		})
+	}, nil)
+	t.SetDoneFunc(func() {
+		// BUG: if the current timer is the last one in the queue,
+		// then a stream of more than one second leads to a race
+		// condition where the timer ticks one extra second. This
+		// shows a negative duration on the clock.
+		go app.QueueUpdateDraw(func() {
+			q.Done()
+		})
+		playChime()
+		// In lieu of above bug, don't wait for the stream to, just
+		// in case it turns out to be longer than one seoncd.
+		time.AfterFunc(800*time.Millisecond, func() {
+			app.QueueUpdateDraw(func() {
+				advancing = true
+				q.Next()
+				advancing = false
+			})
+		})
+	})
 
 	type info struct {
 		km     widget.KeyMap
@@ -334,7 +856,7 @@ func Timer(app *tview.Application, durations []int) *tview.Application {
 		action func()
 	}
 	interactions := struct {
-		prev, next, playpause, restart, quit info
+		prev, next, playpause, restart, quit, format info
 	}{
 		prev: info{
 			km:     widget.KeyMap{Key: "p", Desc: "prev"},
@@ -356,14 +878,26 @@ func Timer(app *tview.Application, durations []int) *tview.Application {
 			km:     widget.KeyMap{Key: "q", Desc: "quit"},
 			button: nil,
 		},
+		format: info{
+			km:     widget.KeyMap{Key: "f", Desc: "format"},
+			button: nil,
+		},
 	}
 
+	formats := clockFormats(t, t.TotalSeconds)
+	format := 0
+	q.SetDurationFormat(formats[format]())
+
 	interactions.next.action = func() {
 		q.Next()
 	}
 	interactions.prev.action = func() {
 		q.Previous()
 	}
+	interactions.format.action = func() {
+		format = (format + 1) % len(formats)
+		q.SetDurationFormat(formats[format]())
+	}
 	interactions.restart.action = func() {
 		t.Restart()
 	}
@@ -378,6 +912,22 @@ func Timer(app *tview.Application, durations []int) *tview.Application {
 		app.Stop()
 	}
 
+	// Selecting a done item of the queue asks whether to restart it.
+	pages := tview.NewPages()
+	prompt := tview.NewModal().AddButtons([]string{"Restart", "Cancel"})
+	q.SetDoneSelectedFunc(func(row int) {
+		prompt.SetText(fmt.Sprintf("Timer %d is done. Restart it?", row+1))
+		prompt.SetDoneFunc(func(_ int, label string) {
+			pages.HidePage("restart")
+			app.SetFocus(q)
+			if label == "Restart" {
+				q.Select(row)
+			}
+		})
+		pages.ShowPage("restart")
+		app.SetFocus(prompt)
+	})
+
 	var setSelectedButton = func(interaction info) {
 		interaction.button.SetSelectedFunc(func() {
 			interaction.action()
@@ -414,9 +964,13 @@ func Timer(app *tview.Application, durations []int) *tview.Application {
 
 	t.Started = func() {
 		interactions.playpause.button.SetLabel("❚❚ pause")
+		q.SetStatus(q.Head(), widget.ItemRunning)
 	}
 	t.Stopped = func() {
 		interactions.playpause.button.SetLabel("▶ play")
+		if q.Status(q.Head()) == widget.ItemRunning {
+			q.SetStatus(q.Head(), widget.ItemPaused)
+		}
 	}
 
 	bc := widget.NewButtonColumn([]*tview.Button{
@@ -427,11 +981,16 @@ func Timer(app *tview.Application, durations []int) *tview.Application {
 	hv := widget.NewHelpView([]widget.KeyMap{
 		interactions.prev.km, interactions.playpause.km,
 		interactions.restart.km, interactions.next.km, interactions.quit.km,
+		interactions.format.km,
 	})
 	hv.SetDynamicColors(true)
 	hv.SetTextAlign(tview.AlignCenter)
 
 	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Leave the keys to the prompt while it is shown.
+		if name, _ := pages.GetFrontPage(); name == "restart" {
+			return event
+		}
 		switch event.Key() {
 		case tcell.KeyRune:
 			switch event.Rune() {
@@ -450,27 +1009,60 @@ func Timer(app *tview.Application, durations []int) *tview.Application {
 			case ' ':
 				interactions.playpause.action()
 				return nil
+			case 'f':
+				interactions.format.action()
+				return nil
 			}
 		}
 		return event
 	})
 
 	f := tview.NewFlex().SetDirection(tview.FlexRow)
-	f.AddItem(t, 0, 2, false)
-	f.AddItem(p, 0, 1, false)
+	switch {
+	case ring:
+		f.AddItem(r, 0, 4, false)
+	case analog:
+		f.AddItem(t, 0, 2, false)
+		f.AddItem(a, 0, 2, false)
+	case barVertical:
+		f.AddItem(t, 0, 2, false)
+	default:
+		f.AddItem(t, 0, 2, false)
+		f.AddItem(p, 0, 1, false)
+	}
+	if len(durations) > 1 {
+		f.AddItem(total, 0, 1, false)
+	}
 	f.AddItem(bc, 0, 2, false)
 	f.AddItem(hv, 2, 1, false)
 
 	t.SetVerticalAlign(widget.AlignDown)
 	t.SetBorderPadding(1, 1, 2, 2)
+	if ring {
+		t.SetVerticalAlign(widget.AlignCenter)
+		t.SetBorderPadding(0, 0, 0, 0)
+		r.SetBorderPadding(1, 0, 2, 2)
+	}
 	p.SetAlign(widget.AlignCenter)
 	p.SetBorderPadding(0, 0, 2, 2)
+	total.SetAlign(widget.AlignCenter)
+	total.SetBorderPadding(0, 0, 2, 2)
+	if barVertical {
+		p.SetBorderPadding(1, 1, 1, 1)
+	}
 	bc.SetVerticalAlign(widget.AlignUp)
 	bc.SetBorderPadding(1, 1, 2, 2)
 
 	root := tview.NewFlex()
 	root.AddItem(q, 0, 1, true)
 	root.AddItem(f, 0, 3, false)
+	if barVertical && !analog && !ring {
+		thickness := 1
+		if barStyle == widget.ProgressBox {
+			thickness = 3
+		}
+		root.AddItem(p, thickness+2, 0, false)
+	}
 
 	SetTheme = func() {
 		q.SetBorder(true)
@@ -482,12 +1074,25 @@ func Timer(app *tview.Application, durations []int) *tview.Application {
 		q.SetCellStyle(tcell.StyleDefault.Foreground(ColorForeground))
 		t.SetBackgroundColor(ColorBackground)
 		p.SetBackgroundColor(ColorBackground)
+		a.SetBackgroundColor(ColorBackground)
+		r.SetBackgroundColor(ColorBackground)
+		total.SetBackgroundColor(ColorBackground)
 		bc.SetBackgroundColor(ColorBackground)
 		hv.SetBackgroundColor(ColorBackground)
 		t.TextColor = ColorForeground
 		t.ShadowColor = ColorShadow
 		p.TextColor = ColorForeground
 		p.ShadowColor = ColorShadow
+		a.TextColor = ColorForeground
+		a.ShadowColor = ColorShadow
+		r.TextColor = ColorForeground
+		r.ShadowColor = ColorShadow
+		total.TextColor = ColorSecondary
+		total.ShadowColor = ColorShadow
+		prompt.SetBackgroundColor(ColorSurface)
+		prompt.SetTextColor(ColorForeground)
+		prompt.SetButtonBackgroundColor(ColorPrimary)
+		prompt.SetButtonTextColor(ColorForeground)
 		hv.SetKeyStyle(tcell.StyleDefault.Foreground(ColorSurface))
 		hv.SetDescStyle(tcell.StyleDefault.Foreground(ColorBorder))
 		hv.SetSeparatorStyle(tcell.StyleDefault.Foreground(ColorBorder))
@@ -503,14 +1108,264 @@ func Timer(app *tview.Application, durations []int) *tview.Application {
 		setButtonColor(interactions.playpause.button)
 	}
 
-	t.Start()
-	return app.SetRoot(root, true)
+	if q.Hold(0) {
+		t.Stopped()
+	} else {
+		t.Start()
+	}
+	pages.AddPage("timer", root, true, true)
+	pages.AddPage("restart", prompt, true, false)
+
+	return app.SetRoot(pages, true)
+}
+
+// compactFlag is a flag.Value of the compact rendering of the clock,
+// one of widget.CompactNone, widget.CompactHalfBlock and
+// widget.CompactBraille.
+type compactFlag int
+
+var compactNames = map[string]compactFlag{
+	"":        widget.CompactNone,
+	"half":    widget.CompactHalfBlock,
+	"braille": widget.CompactBraille,
+}
+
+func (c *compactFlag) String() string {
+	for name, v := range compactNames {
+		if v == *c {
+			return name
+		}
+	}
+	return ""
+}
+
+func (c *compactFlag) Set(s string) error {
+	v, ok := compactNames[s]
+	if !ok {
+		return fmt.Errorf("must be half or braille")
+	}
+	*c = v
+	return nil
+}
+
+// barStyleFlag is a flag.Value of the style of the progress bar, one of
+// widget.ProgressBox, widget.ProgressThin, widget.ProgressBraille and
+// widget.ProgressASCII.
+type barStyleFlag int
+
+var barStyleNames = map[string]barStyleFlag{
+	"box":     widget.ProgressBox,
+	"thin":    widget.ProgressThin,
+	"braille": widget.ProgressBraille,
+	"ascii":   widget.ProgressASCII,
+}
+
+func (b *barStyleFlag) String() string {
+	for name, v := range barStyleNames {
+		if v == *b {
+			return name
+		}
+	}
+	return ""
+}
+
+func (b *barStyleFlag) Set(s string) error {
+	v, ok := barStyleNames[s]
+	if !ok {
+		return fmt.Errorf("must be box, thin, braille or ascii")
+	}
+	*b = v
+	return nil
+}
+
+// barLabelFlag is a flag.Value of the label of the progress bar, one of
+// percent, elapsed, remaining or end.
+type barLabelFlag string
+
+func (b *barLabelFlag) String() string {
+	return string(*b)
+}
+
+func (b *barLabelFlag) Set(s string) error {
+	switch s {
+	case "", "percent", "elapsed", "remaining", "end":
+		*b = barLabelFlag(s)
+		return nil
+	}
+	return fmt.Errorf("must be percent, elapsed, remaining or end")
+}
+
+// label returns the function that labels p, the progress bar of the
+// timer t, or nil if b is empty.
+func (b barLabelFlag) label(p *widget.ProgressBar, t *widget.Clock) func() string {
+	switch b {
+	case "percent":
+		return func() string {
+			return fmt.Sprintf("%d%%", p.Percent())
+		}
+	case "elapsed":
+		return func() string {
+			return widget.SecondWithColons(t.ElapsedSeconds())
+		}
+	case "remaining":
+		return func() string {
+			return "-" + widget.SecondWithColons(t.TotalSeconds()-t.ElapsedSeconds())
+		}
+	case "end":
+		return func() string {
+			return "ends " + time.Now().Add(t.Value()).Format("15:04:05")
+		}
+	}
+	return nil
+}
+
+// templateList is a flag.Value of the templates given to a flag that
+// may be given more than once.
+type templateList struct {
+	templates []*widget.Template
+
+	// replace is whether the next template replaces, instead of being
+	// added to, the templates given so far.
+	replace bool
+}
+
+func (l *templateList) String() string {
+	var ss []string
+	for _, t := range l.templates {
+		ss = append(ss, t.String())
+	}
+	return strings.Join(ss, ", ")
+}
+
+func (l *templateList) Set(s string) error {
+	t, err := widget.ParseTemplate(s)
+	if err != nil {
+		return err
+	}
+	if l.replace {
+		l.templates, l.replace = nil, false
+	}
+	l.templates = append(l.templates, t)
+	return nil
+}
+
+// clockFormats returns the formats that the format key cycles through.
+// Each one sets the format of c, and returns the format for the table
+// cells. They are the templates given with -format or, without any,
+// SecondWithLetters and SecondWithColons, which keep the fields that
+// longest would have if -fixed is set.
+func clockFormats(c *widget.Clock, longest func() int) []func() func(seconds int) string {
+	var fs []func() func(seconds int) string
+	for _, t := range templates.templates {
+		t := t
+		fs = append(fs, func() func(seconds int) string {
+			c.SetTemplate(t)
+			return t.Seconds
+		})
+	}
+	if len(fs) > 0 {
+		return fs
+	}
+
+	letters, colons := widget.SecondWithLetters, widget.SecondWithColons
+	if fixed {
+		letters = func(seconds int) string {
+			return widget.SecondWithLettersFixed(seconds, longest())
+		}
+		colons = func(seconds int) string {
+			return widget.SecondWithColonsFixed(seconds, longest())
+		}
+	}
+	for _, format := range []func(seconds int) string{letters, colons} {
+		format := format
+		fs = append(fs, func() func(seconds int) string {
+			c.SetTemplate(nil)
+			c.Format = format
+			return widget.SecondWithColons
+		})
+	}
+	return fs
+}
+
+// newClock applies the flags that set the look of every clock, -font,
+// -tabular and -compact, to c, and returns c.
+func newClock(c *widget.Clock) *widget.Clock {
+	c.Font = font
+	c.TabularFigures = tabular
+	c.Compact = int(compact)
+	return c
 }
 
+// formatCycle returns a function that sets clocks to the next of the
+// formats that the format key cycles through, starting with the first,
+// and returns it's template, or nil if it has none. The formats are the
+// templates given with -format or, without any, fallback, if set,
+// followed by SecondWithColons and SecondWithLetters, which keep the
+// fields that longest would have if -fixed is set.
+func formatCycle(longest func() int, fallback *widget.Template, clocks ...*widget.Clock) func() *widget.Template {
+	ts := templates.templates
+	if len(ts) == 0 && fallback != nil {
+		ts = []*widget.Template{fallback}
+	}
+	var cycle []func() *widget.Template
+	for _, t := range ts {
+		t := t
+		cycle = append(cycle, func() *widget.Template {
+			for _, c := range clocks {
+				c.SetTemplate(t)
+			}
+			return t
+		})
+	}
+	if len(templates.templates) == 0 {
+		var formats [][]func() func(seconds int) string
+		for _, c := range clocks {
+			formats = append(formats, clockFormats(c, longest))
+		}
+		// clockFormats has letters first; these clocks start with
+		// colons.
+		for _, i := range []int{1, 0} {
+			i := i
+			cycle = append(cycle, func() *widget.Template {
+				for _, fs := range formats {
+					fs[i]()
+				}
+				return nil
+			})
+		}
+	}
+	format := -1
+	return func() *widget.Template {
+		format = (format + 1) % len(cycle)
+		return cycle[format]()
+	}
+}
+
+// tenthsTemplate returns the template of the clocks that show the tenths
+// of a second unless a format is given.
+func tenthsTemplate() *widget.Template {
+	// NOTE: error ignored; the template is valid.
+	t, _ := widget.ParseTemplate("%M:%S.%f")
+	return t
+}
+
+// writeToBytes returns what write writes to it's writer.
+func writeToBytes(write func(w io.Writer) error) []byte {
+	var b bytes.Buffer
+	// NOTE: error ignored; writes to a bytes.Buffer don't fail.
+	write(&b)
+	return b.Bytes()
+}
+
+// unitDuration matches durations made of numbers followed by their
+// unit, like 2d3h15m or 1h 30m.
+var unitDuration = regexp.MustCompile(`^(?:(\d+)d)? *(?:(\d+)h)? *(?:(\d+)m)? *(?:(\d+)s)?$`)
+
 // ParseDuration returns the total number of seconds in dur, which must
-// be of format [[hh:]mm:]ss.
+// be of format [[[dd:]hh:]mm:]ss, or of numbers followed by their unit,
+// one of d, h, m and s in that order, like 2d3h15m or 1h 30m.
 func ParseDuration(dur string) (int, error) {
-	var hr, min, sec int
+	var day, hr, min, sec int
 
 	if m, err := regexp.MatchString(`^\d*$`, dur); m {
 		if err != nil {
@@ -539,11 +1394,32 @@ func ParseDuration(dur string) (int, error) {
 		if err = checkField(sec, min); err != nil {
 			return 0, err
 		}
+	} else if m, err := regexp.MatchString(`^\d+:\d{2}:\d{2}:\d{2}$`, dur); m {
+		if err != nil {
+			return 0, err
+		}
+		s := strings.Split(dur, ":")
+		day, _ = strconv.Atoi(s[0])
+		hr, _ = strconv.Atoi(s[1])
+		min, _ = strconv.Atoi(s[2])
+		sec, _ = strconv.Atoi(s[3])
+		if err = checkField(sec, min); err != nil {
+			return 0, err
+		}
+		if hr >= 24 {
+			return 0, fmt.Errorf("hour's field must be less than 24")
+		}
+	} else if m := unitDuration.FindStringSubmatch(dur); m != nil && strings.TrimSpace(dur) != "" {
+		// Fields are not bound by the next larger unit, 90m is fine.
+		day, _ = strconv.Atoi(m[1])
+		hr, _ = strconv.Atoi(m[2])
+		min, _ = strconv.Atoi(m[3])
+		sec, _ = strconv.Atoi(m[4])
 	} else {
-		return 0, fmt.Errorf("duration must be in [[hh:]mm:]ss format")
+		return 0, fmt.Errorf("duration must be in [[[dd:]hh:]mm:]ss format, or like 2d3h15m")
 	}
 
-	return (hr * 3600) + (min * 60) + sec, nil
+	return (day * 86400) + (hr * 3600) + (min * 60) + sec, nil
 }
 
 // checkField returns error if sec/min field are not less than 60.
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ValenTheRed/watch/internal/widget"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	if font, err = widget.LoadFont(fontName); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		dur     string
+		want    int
+		wantErr bool
+	}{
+		{"5", 5, false},
+		{"1200", 1200, false},
+		{"4:32", 272, false},
+		{"120:00", 7200, false},
+		{"1:23:00", 4980, false},
+		{"2:03:15:00", 184500, false},
+		{"2d3h15m", 184500, false},
+		{"1h 30m", 5400, false},
+		{"90s", 90, false},
+		// Fields with units aren't bound by the next larger unit.
+		{"90m", 5400, false},
+		{"1d", 86400, false},
+		{"4:60", 0, true},
+		{"1:60:00", 0, true},
+		{"1:24:00:00", 0, true},
+		{"3h2d", 0, true},
+		{"1h30", 0, true},
+		{" ", 0, true},
+		{"5x", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.dur)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDuration(%q) error = %v, want error %v", tt.dur, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %d, want %d", tt.dur, got, tt.want)
+		}
+	}
+}
+
+// runApp runs app on a simulation screen until the test ends.
+func runApp(t *testing.T, app *tview.Application) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	app.SetScreen(screen)
+	SetTheme()
+	go app.Run()
+	t.Cleanup(app.Stop)
+}
+
+// pressAfter presses key once wait has passed, while keeping the UI
+// busy for all of wait, so that a clock that runs out meanwhile hands
+// off to the UI as the key restarts it. It fails the test if the key
+// doesn't return.
+func pressAfter(t *testing.T, app *tview.Application, wait time.Duration, key rune) {
+	t.Helper()
+	pressed := make(chan struct{})
+	go app.QueueUpdate(func() {
+		time.Sleep(wait)
+		app.GetInputCapture()(tcell.NewEventKey(tcell.KeyRune, key, tcell.ModNone))
+		close(pressed)
+	})
+	select {
+	case <-pressed:
+	case <-time.After(wait + 2*time.Second):
+		t.Fatalf("pressing %q hung", key)
+	}
+}
+
+func TestTimerRestartAsDone(t *testing.T) {
+	app := Timer(tview.NewApplication(), []int{1}, []bool{false})
+	runApp(t, app)
+	pressAfter(t, app, 1500*time.Millisecond, 'r')
+}
+
+func TestStopwatchRestLapAsDone(t *testing.T) {
+	defer func(r durationFlag) { rest = r }(rest)
+	rest = 1
+	app := Stopwatch(tview.NewApplication())
+	runApp(t, app)
+	// The first lap starts the rest, and the second one restarts it as
+	// it runs out.
+	pressAfter(t, app, 0, 'l')
+	pressAfter(t, app, 1500*time.Millisecond, 'l')
+}
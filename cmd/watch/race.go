@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ValenTheRed/watch/internal/widget"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"golang.design/x/clipboard"
+)
+
+// Race parses the arguments of the race command, and returns a function
+// that sets up app to time the finishes of a race. The only argument, if
+// any, is a file of the entries of the race.
+func Race(args []string) (func(app *tview.Application) *tview.Application, error) {
+	fs := newFlagSet("race")
+	fs.StringVar(&resultsOut, "results-out", resultsOut, "")
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		return nil, fmt.Errorf("race takes at most an entries file")
+	}
+	var entries map[string]string
+	if fs.NArg() == 1 {
+		var err error
+		if entries, err = loadEntries(fs.Arg(0)); err != nil {
+			return nil, err
+		}
+	}
+	return func(app *tview.Application) *tview.Application {
+		return RaceApp(app, entries)
+	}, nil
+}
+
+// resultsOut is the file the results of a race are written to, in place
+// of the clipboard, if set.
+var resultsOut string
+
+// loadEntries returns the names of the competitors by bib in file, one
+// bib, followed by the name, per line. Blank lines and lines starting
+// with '#' are ignored.
+func loadEntries(file string) (map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("race: %v", err)
+	}
+	defer f.Close()
+
+	entries := make(map[string]string)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		entries[fields[0]] = strings.Join(fields[1:], " ")
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("race: %s: %v", file, err)
+	}
+	return entries, nil
+}
+
+// writeResultsCSV writes results to w as CSV, with a header record.
+func writeResultsCSV(w io.Writer, results []widget.Result) error {
+	records := [][]string{{"place", "bib", "name", "time", "time_seconds", "at", "flag"}}
+	for _, res := range results {
+		place, flag := "", ""
+		if res.Place != 0 {
+			place = fmt.Sprint(res.Place)
+		}
+		switch {
+		case res.Duplicate:
+			flag = "duplicate"
+		case res.Unknown:
+			flag = "unknown"
+		}
+		records = append(records, []string{
+			place, res.Bib, res.Name,
+			widget.DurationWithColons(res.Time),
+			fmt.Sprintf("%.1f", res.Time.Seconds()),
+			res.At.Format(lapTimeLayout),
+			flag,
+		})
+	}
+	return csv.NewWriter(w).WriteAll(records)
+}
+
+// exportResults returns results as CSV.
+func exportResults(results []widget.Result) []byte {
+	return writeToBytes(func(w io.Writer) error {
+		return writeResultsCSV(w, results)
+	})
+}
+
+// writeResultsFile writes results to -results-out.
+func writeResultsFile(results []widget.Result) error {
+	if err := os.WriteFile(resultsOut, exportResults(results), 0o644); err != nil {
+		return fmt.Errorf("race: %v", err)
+	}
+	return nil
+}
+
+// RaceApp returns app after setting the root to time the finishes of the
+// competitors in entries, the names of the competitors by bib. A bib not
+// in entries is flagged as unknown, unless there are no entries.
+func RaceApp(app *tview.Application, entries map[string]string) *tview.Application {
+	s := newClock(widget.NewStopwatch())
+	s.Changed = func() {
+		app.Draw()
+	}
+	// Finishes are close, so the tenths of a second are shown unless a
+	// format is given. A stopwatch has no end, so it keeps it's hours
+	// with -fixed.
+	nextFormat := formatCycle(func() int { return 3600 }, tenthsTemplate(), s)
+	nextFormat()
+	rt := widget.NewResultTable(entries)
+
+	// The bib is typed into bib, and recorded with enter. Bibs are only
+	// numbers, which leaves the letters to the keys.
+	bib := tview.NewInputField()
+	bib.SetLabel("bib ")
+	bib.SetAcceptanceFunc(tview.InputFieldInteger)
+	bib.SetBorder(true)
+
+	status := tview.NewTextView()
+	status.SetTextAlign(tview.AlignCenter)
+
+	type info struct {
+		km     widget.KeyMap
+		action func()
+	}
+	interactions := struct {
+		finish, playpause, undo, sort, copy, format, quit info
+	}{
+		finish: info{
+			km: widget.KeyMap{Key: "enter", Desc: "finish"},
+		},
+		playpause: info{
+			km: widget.KeyMap{Key: "space", Desc: "start/pause"},
+		},
+		undo: info{
+			km: widget.KeyMap{Key: "u", Desc: "undo finish"},
+		},
+		sort: info{
+			km: widget.KeyMap{Key: "o", Desc: "sort"},
+		},
+		copy: info{
+			km: widget.KeyMap{Key: "y/c", Desc: "copy results"},
+		},
+		format: info{
+			km: widget.KeyMap{Key: "f", Desc: "format"},
+		},
+		quit: info{
+			km: widget.KeyMap{Key: "q", Desc: "quit"},
+		},
+	}
+
+	interactions.finish.action = func() {
+		text := bib.GetText()
+		if text == "" {
+			return
+		}
+		if s.Value() == 0 {
+			status.SetText("the race hasn't started")
+			return
+		}
+		res := rt.Finish(text, s.Value())
+		bib.SetText("")
+		switch {
+		case res.Duplicate:
+			status.SetText(fmt.Sprintf("bib %s has already finished", res.Bib))
+		case res.Unknown:
+			status.SetText(fmt.Sprintf("bib %s isn't an entry", res.Bib))
+		default:
+			status.SetText(fmt.Sprintf("bib %s finished #%d in %s", res.Bib, res.Place, rt.Format(res.Time)))
+		}
+	}
+	interactions.playpause.action = func() {
+		if s.Running() {
+			s.Stop()
+		} else {
+			s.Start()
+		}
+	}
+	interactions.undo.action = func() {
+		if rt.Undo() {
+			status.SetText("took back the last finish")
+		}
+	}
+	interactions.sort.action = func() {
+		if rt.SortBy() == widget.SortByTime {
+			rt.SetSortBy(widget.SortByBib)
+			status.SetText("sorted by bib")
+		} else {
+			rt.SetSortBy(widget.SortByTime)
+			status.SetText("sorted by time")
+		}
+	}
+	interactions.copy.action = func() {
+		if resultsOut != "" {
+			if err := writeResultsFile(rt.Results()); err != nil {
+				status.SetText(err.Error())
+				return
+			}
+			status.SetText("results written to " + resultsOut)
+			return
+		}
+		clipboard.Write(clipboard.FmtText, exportResults(rt.Results()))
+		status.SetText("results copied")
+	}
+	interactions.format.action = func() {
+		nextFormat()
+	}
+	interactions.quit.action = func() {
+		app.Stop()
+	}
+
+	Cleanup = func() error {
+		if resultsOut == "" || rt.GetRowCount() == 0 {
+			return nil
+		}
+		return writeResultsFile(rt.Results())
+	}
+
+	bib.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			interactions.finish.action()
+		}
+	})
+
+	hv := widget.NewHelpView([]widget.KeyMap{
+		interactions.finish.km, interactions.playpause.km,
+		interactions.undo.km, interactions.sort.km,
+		interactions.copy.km, interactions.format.km,
+		interactions.quit.km,
+	})
+	hv.SetDynamicColors(true)
+	hv.SetTextAlign(tview.AlignCenter)
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case ' ':
+				interactions.playpause.action()
+				return nil
+			case 'u':
+				interactions.undo.action()
+				return nil
+			case 'o':
+				interactions.sort.action()
+				return nil
+			case 'y', 'c':
+				interactions.copy.action()
+				return nil
+			case 'f':
+				interactions.format.action()
+				return nil
+			case 'q':
+				interactions.quit.action()
+				return nil
+			}
+		}
+		return event
+	})
+
+	f := tview.NewFlex().SetDirection(tview.FlexRow)
+	f.AddItem(s, 0, 1, false)
+	f.AddItem(tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(bib, 24, 0, true).
+		AddItem(nil, 0, 1, false), 3, 0, true)
+	f.AddItem(status, 1, 0, false)
+	f.AddItem(hv, 2, 1, false)
+
+	s.SetVerticalAlign(widget.AlignCenter)
+	s.SetBorderPadding(1, 1, 2, 2)
+
+	root := tview.NewFlex()
+	root.AddItem(rt, 0, 1, false)
+	root.AddItem(f, 0, 2, true)
+
+	SetTheme = func() {
+		rt.SetBorder(true)
+		rt.SetBorderColor(ColorSecondary)
+		rt.SetSelectedStyle(tcell.StyleDefault.Background(ColorPrimary))
+		rt.SetBackgroundColor(ColorBackground)
+		rt.SetHeaderStyle(tcell.StyleDefault.Foreground(ColorForeground))
+		rt.SetUnderlineStyle(tcell.StyleDefault.Foreground(ColorSecondary))
+		rt.SetCellStyle(tcell.StyleDefault.Foreground(ColorForeground))
+		rt.SetFlagStyle(tcell.StyleDefault.Foreground(ColorWorst))
+		bib.SetBackgroundColor(ColorBackground)
+		bib.SetBorderColor(ColorSecondary)
+		bib.SetLabelColor(ColorForeground)
+		bib.SetFieldBackgroundColor(ColorSurface)
+		bib.SetFieldTextColor(ColorForeground)
+		status.SetBackgroundColor(ColorBackground)
+		status.SetTextColor(ColorSecondary)
+		s.SetBackgroundColor(ColorBackground)
+		hv.SetBackgroundColor(ColorBackground)
+		s.TextColor = ColorForeground
+		s.ShadowColor = ColorShadow
+		hv.SetKeyStyle(tcell.StyleDefault.Foreground(ColorSurface))
+		hv.SetDescStyle(tcell.StyleDefault.Foreground(ColorBorder))
+		hv.SetSeparatorStyle(tcell.StyleDefault.Foreground(ColorBorder))
+	}
+
+	return app.SetRoot(root, true).SetFocus(bib)
+}
@@ -0,0 +1,427 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ValenTheRed/watch/internal/widget"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Splits parses the arguments of the splits command, and returns a
+// function that sets up app to time a run of the segments in the file
+// given in args.
+func Splits(args []string) (func(app *tview.Application) *tview.Application, error) {
+	fs := newFlagSet("splits")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return nil, fmt.Errorf("splits takes a segments file")
+	}
+	file := fs.Arg(0)
+	segments, err := loadSegments(file)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := loadRecords(recordsPath(file))
+	if err != nil {
+		return nil, err
+	}
+	mergeRecords(segments, saved)
+	return func(app *tview.Application) *tview.Application {
+		return SplitsApp(app, segments, recordsPath(file))
+	}, nil
+}
+
+// loadSegments returns the segments in file, which is either a LiveSplit
+// splits file (.lss), or a text file of the names of the segments, one
+// per line. Blank lines and lines starting with '#' are ignored.
+func loadSegments(file string) ([]widget.Segment, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("splits: %v", err)
+	}
+	defer f.Close()
+
+	var segments []widget.Segment
+	if strings.EqualFold(filepath.Ext(file), ".lss") {
+		segments, err = parseLSS(f)
+	} else {
+		segments, err = parseSegments(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("splits: %s: %v", file, err)
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("splits: %s: no segments", file)
+	}
+	return segments, nil
+}
+
+// parseSegments returns the segments named one per line in r.
+func parseSegments(r io.Reader) ([]widget.Segment, error) {
+	var segments []widget.Segment
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		segments = append(segments, widget.Segment{Name: line})
+	}
+	return segments, sc.Err()
+}
+
+// parseLSS returns the segments of the LiveSplit splits file in r, with
+// their personal best and best segment times, in real time.
+func parseLSS(r io.Reader) ([]widget.Segment, error) {
+	var run struct {
+		Segments []struct {
+			Name       string
+			SplitTimes []struct {
+				Name     string `xml:"name,attr"`
+				RealTime string
+			} `xml:"SplitTimes>SplitTime"`
+			BestSegmentTime struct {
+				RealTime string
+			}
+		} `xml:"Segments>Segment"`
+	}
+	if err := xml.NewDecoder(r).Decode(&run); err != nil {
+		return nil, err
+	}
+	segments := make([]widget.Segment, len(run.Segments))
+	for i, seg := range run.Segments {
+		segments[i].Name = seg.Name
+		for _, split := range seg.SplitTimes {
+			if split.Name == "Personal Best" {
+				segments[i].PB = parseLSSTime(split.RealTime)
+			}
+		}
+		segments[i].Best = parseLSSTime(seg.BestSegmentTime.RealTime)
+	}
+	return segments, nil
+}
+
+// parseLSSTime returns the time in s, which is like 00:01:23.4560000,
+// or 0 if there is none.
+func parseLSSTime(s string) time.Duration {
+	fields := strings.Split(strings.TrimSpace(s), ":")
+	if len(fields) != 3 {
+		return 0
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute} {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return 0
+		}
+		d += time.Duration(n) * unit
+	}
+	sec, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return 0
+	}
+	return d + time.Duration(sec*float64(time.Second))
+}
+
+// record is the personal best and best time of a segment, as saved.
+type record struct {
+	Name string `json:"name"`
+	PB   int64  `json:"pb_ms,omitempty"`
+	Best int64  `json:"best_ms,omitempty"`
+}
+
+// recordsPath returns the path the records of the run in the segments
+// file are saved at, which is watch/splits inside of the user's config
+// directory. The records are keyed by the absolute path of file, so that
+// segments files of the same name in different directories don't share
+// them.
+func recordsPath(file string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return ""
+	}
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	sum := sha1.Sum([]byte(abs))
+	return filepath.Join(dir, "watch", "splits", fmt.Sprintf("%s-%x.json", name, sum[:4]))
+}
+
+// loadRecords returns the records saved at path. A missing file has no
+// records.
+func loadRecords(path string) ([]record, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("splits: %v", err)
+	}
+	var records []record
+	if err := json.Unmarshal(b, &records); err != nil {
+		return nil, fmt.Errorf("splits: %s: %v", path, err)
+	}
+	return records, nil
+}
+
+// saveRecords saves the personal best and best times of segments at
+// path.
+func saveRecords(path string, segments []widget.Segment) error {
+	if path == "" {
+		return nil
+	}
+	records := make([]record, len(segments))
+	for i, seg := range segments {
+		records[i] = record{seg.Name, seg.PB.Milliseconds(), seg.Best.Milliseconds()}
+	}
+	b, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("splits: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("splits: %v", err)
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return fmt.Errorf("splits: %v", err)
+	}
+	return nil
+}
+
+// mergeRecords sets the times of segments to those saved, for the
+// segments that have the name saved at their place. The saved times
+// replace those of the segments file.
+func mergeRecords(segments []widget.Segment, saved []record) {
+	for i := range segments {
+		if i >= len(saved) || saved[i].Name != segments[i].Name {
+			continue
+		}
+		if saved[i].PB != 0 {
+			segments[i].PB = time.Duration(saved[i].PB) * time.Millisecond
+		}
+		if saved[i].Best != 0 {
+			segments[i].Best = time.Duration(saved[i].Best) * time.Millisecond
+		}
+	}
+}
+
+// improve returns segments with the best times beaten by the splits
+// of st, and, if the run is done and faster than the personal best,
+// it's splits as the personal best. It also returns whether any time
+// was beaten.
+func improve(st *widget.SplitTable) ([]widget.Segment, bool) {
+	segments := st.Segments()
+	splits := st.Splits()
+	improved := false
+	for i := range splits {
+		if t := st.SegmentTime(i); segments[i].Best == 0 || t < segments[i].Best {
+			segments[i].Best = t
+			improved = true
+		}
+	}
+	last := len(segments) - 1
+	if st.Done() && (segments[last].PB == 0 || splits[last] < segments[last].PB) {
+		for i := range segments {
+			segments[i].PB = splits[i]
+		}
+		improved = true
+	}
+	return segments, improved
+}
+
+// SplitsApp returns app after setting the root to time a run of
+// segments, whose records are saved at path.
+func SplitsApp(app *tview.Application, segments []widget.Segment, path string) *tview.Application {
+	s := newClock(widget.NewStopwatch())
+	s.Changed = func() {
+		app.Draw()
+	}
+	st := widget.NewSplitTable(segments)
+	// Runs are close, so the tenths of a second are shown unless a
+	// format is given. A stopwatch has no end, so it keeps it's hours
+	// with -fixed.
+	tenths := tenthsTemplate()
+	nextFormat := formatCycle(func() int { return 3600 }, tenths, s)
+	// setFormat sets the clock, and the split table, to the next format.
+	var setFormat = func() {
+		format := widget.DurationWithColons
+		if t := nextFormat(); t != nil && t != tenths {
+			format = t.Format
+		}
+		st.SetFormat(format)
+	}
+	setFormat()
+
+	type info struct {
+		km     widget.KeyMap
+		action func()
+	}
+	interactions := struct {
+		split, undo, playpause, reset, format, quit info
+	}{
+		split: info{
+			km: widget.KeyMap{Key: "l", Desc: "split"},
+		},
+		undo: info{
+			km: widget.KeyMap{Key: "u", Desc: "undo split"},
+		},
+		playpause: info{
+			km: widget.KeyMap{Key: "space", Desc: "play/pause"},
+		},
+		reset: info{
+			km: widget.KeyMap{Key: "r", Desc: "reset"},
+		},
+		format: info{
+			km: widget.KeyMap{Key: "f", Desc: "format"},
+		},
+		quit: info{
+			km: widget.KeyMap{Key: "q", Desc: "quit"},
+		},
+	}
+
+	// saveErr is the last error saving the records, returned by Cleanup.
+	var saveErr error
+	// save saves the records of the run, if it has improved any.
+	var save = func() []widget.Segment {
+		segments, improved := improve(st)
+		if improved {
+			saveErr = saveRecords(path, segments)
+		}
+		return segments
+	}
+
+	interactions.split.action = func() {
+		if st.Done() {
+			return
+		}
+		// The first split starts the run.
+		if s.Value() == 0 {
+			s.Start()
+			return
+		}
+		st.Split(s.Value())
+		if st.Done() {
+			s.Stop()
+			save()
+		}
+	}
+	interactions.undo.action = func() {
+		if len(st.Splits()) == 0 {
+			return
+		}
+		// Only the split is taken back; a paused, or finished, run
+		// stays as it is.
+		st.Undo()
+	}
+	interactions.playpause.action = func() {
+		if st.Done() {
+			return
+		}
+		if s.Running() {
+			s.Stop()
+		} else {
+			s.Start()
+		}
+	}
+	interactions.reset.action = func() {
+		// The golds of the run being reset are kept.
+		st.SetSegments(save())
+		st.Reset()
+		s.Stop()
+		s.SetElapsed(0)
+	}
+	interactions.format.action = func() {
+		setFormat()
+	}
+	interactions.quit.action = func() {
+		app.Stop()
+	}
+
+	Cleanup = func() error {
+		save()
+		return saveErr
+	}
+
+	hv := widget.NewHelpView([]widget.KeyMap{
+		interactions.split.km, interactions.undo.km,
+		interactions.playpause.km, interactions.reset.km,
+		interactions.format.km, interactions.quit.km,
+	})
+	hv.SetDynamicColors(true)
+	hv.SetTextAlign(tview.AlignCenter)
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'l':
+				interactions.split.action()
+				return nil
+			case 'u':
+				interactions.undo.action()
+				return nil
+			case ' ':
+				interactions.playpause.action()
+				return nil
+			case 'r':
+				interactions.reset.action()
+				return nil
+			case 'f':
+				interactions.format.action()
+				return nil
+			case 'q':
+				interactions.quit.action()
+				return nil
+			}
+		}
+		return event
+	})
+
+	f := tview.NewFlex().SetDirection(tview.FlexRow)
+	f.AddItem(s, 0, 1, false)
+	f.AddItem(hv, 2, 1, false)
+
+	s.SetVerticalAlign(widget.AlignCenter)
+	s.SetBorderPadding(1, 1, 2, 2)
+
+	root := tview.NewFlex()
+	root.AddItem(st, 0, 1, true)
+	root.AddItem(f, 0, 2, false)
+
+	SetTheme = func() {
+		st.SetBorder(true)
+		st.SetBorderColor(ColorSecondary)
+		st.SetSelectedStyle(tcell.StyleDefault.Background(ColorPrimary))
+		st.SetBackgroundColor(ColorBackground)
+		st.SetHeaderStyle(tcell.StyleDefault.Foreground(ColorForeground))
+		st.SetUnderlineStyle(tcell.StyleDefault.Foreground(ColorSecondary))
+		st.SetCellStyle(tcell.StyleDefault.Foreground(ColorForeground))
+		st.SetAheadStyle(tcell.StyleDefault.Foreground(ColorBest))
+		st.SetBehindStyle(tcell.StyleDefault.Foreground(ColorWorst))
+		st.SetGoldStyle(tcell.StyleDefault.Foreground(ColorGold))
+		st.SetFooterStyle(tcell.StyleDefault.Foreground(ColorSecondary))
+		s.SetBackgroundColor(ColorBackground)
+		hv.SetBackgroundColor(ColorBackground)
+		s.TextColor = ColorForeground
+		s.ShadowColor = ColorShadow
+		hv.SetKeyStyle(tcell.StyleDefault.Foreground(ColorSurface))
+		hv.SetDescStyle(tcell.StyleDefault.Foreground(ColorBorder))
+		hv.SetSeparatorStyle(tcell.StyleDefault.Foreground(ColorBorder))
+	}
+
+	return app.SetRoot(root, true)
+}
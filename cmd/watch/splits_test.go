@@ -0,0 +1,111 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ValenTheRed/watch/internal/widget"
+)
+
+func TestParseLSS(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    []widget.Segment
+		wantErr bool
+	}{
+		{
+			name: "personal best",
+			src: `<?xml version="1.0" encoding="UTF-8"?>
+<Run version="1.7.0">
+  <Segments>
+    <Segment>
+      <Name>Forest</Name>
+      <SplitTimes>
+        <SplitTime name="Personal Best">
+          <RealTime>00:01:23.4560000</RealTime>
+          <GameTime>00:01:20.0000000</GameTime>
+        </SplitTime>
+      </SplitTimes>
+      <BestSegmentTime>
+        <RealTime>00:01:20.5000000</RealTime>
+      </BestSegmentTime>
+    </Segment>
+    <Segment>
+      <Name>Castle</Name>
+      <SplitTimes>
+        <SplitTime name="Sub-20">
+          <RealTime>00:03:00.0000000</RealTime>
+        </SplitTime>
+        <SplitTime name="Personal Best">
+          <RealTime>01:02:03.1000000</RealTime>
+        </SplitTime>
+      </SplitTimes>
+      <BestSegmentTime>
+        <RealTime>00:59:00.2500000</RealTime>
+      </BestSegmentTime>
+    </Segment>
+  </Segments>
+</Run>`,
+			want: []widget.Segment{
+				{Name: "Forest", PB: 83456 * time.Millisecond, Best: 80500 * time.Millisecond},
+				{Name: "Castle", PB: time.Hour + 2*time.Minute + 3100*time.Millisecond, Best: 59*time.Minute + 250*time.Millisecond},
+			},
+		},
+		{
+			// A run that was never finished has no personal best.
+			name: "no personal best",
+			src: `<Run>
+  <Segments>
+    <Segment>
+      <Name>Forest</Name>
+      <SplitTimes>
+        <SplitTime name="Personal Best" />
+      </SplitTimes>
+      <BestSegmentTime />
+    </Segment>
+    <Segment>
+      <Name>Castle</Name>
+    </Segment>
+  </Segments>
+</Run>`,
+			want: []widget.Segment{{Name: "Forest"}, {Name: "Castle"}},
+		},
+		{
+			name: "no segments",
+			src:  `<Run><Segments /></Run>`,
+			want: []widget.Segment{},
+		},
+		{name: "not xml", src: "Forest\nCastle\n", wantErr: true},
+		{name: "cut off", src: "<Run><Segments><Segment>", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseLSS(strings.NewReader(tt.src))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseLSS() error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseLSS() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseLSSTime(t *testing.T) {
+	tests := []struct {
+		s    string
+		want time.Duration
+	}{
+		{"00:01:23.4560000", 83456 * time.Millisecond},
+		{" 10:00:00 ", 10 * time.Hour},
+		{"01:23", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := parseLSSTime(tt.s); got != tt.want {
+			t.Errorf("parseLSSTime(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ValenTheRed/watch/internal/widget"
+	"github.com/gdamore/tcell/v2"
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/rivo/tview"
+)
+
+// threshold is a named time left of a talk.
+type threshold struct {
+	// left is the time left, in seconds, the threshold is crossed at.
+	left int
+	name string
+}
+
+// thresholdList is a flag.Value of the thresholds given to a flag that
+// may be given more than once, each like 5m, or 5m=wrap up to name it.
+type thresholdList []threshold
+
+func (l *thresholdList) String() string {
+	s := make([]string, len(*l))
+	for i, t := range *l {
+		s[i] = fmt.Sprintf("%s=%s", widget.SecondWithColons(t.left), t.name)
+	}
+	return strings.Join(s, ",")
+}
+
+func (l *thresholdList) Set(s string) error {
+	left, name := s, ""
+	if i := strings.Index(s, "="); i != -1 {
+		left, name = s[:i], strings.TrimSpace(s[i+1:])
+	}
+	seconds, err := ParseDuration(strings.TrimSpace(left))
+	if err != nil {
+		return err
+	}
+	if seconds == 0 {
+		return fmt.Errorf("must be more than 0")
+	}
+	if name == "" {
+		name = widget.SecondWithLetters(seconds) + " left"
+	}
+	*l = append(*l, threshold{seconds, name})
+	return nil
+}
+
+// Talk parses the arguments of the talk command, and returns a function
+// that sets up app to time a talk of the duration given in args.
+func Talk(args []string) (func(app *tview.Application) *tview.Application, error) {
+	var thresholds thresholdList
+	fs := newFlagSet("talk")
+	fs.Var(&thresholds, "warn", "")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return nil, fmt.Errorf("talk takes the duration of the talk")
+	}
+	seconds, err := ParseDuration(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+	if seconds == 0 {
+		return nil, fmt.Errorf("talk: 0 not allowed; only positive integers")
+	}
+	if len(thresholds) == 0 {
+		for _, left := range []int{5 * 60, 60} {
+			if left < seconds {
+				thresholds = append(thresholds, threshold{left, widget.SecondWithLetters(left) + " left"})
+			}
+		}
+	}
+	for _, th := range thresholds {
+		if th.left >= seconds {
+			return nil, fmt.Errorf("talk: -warn %s isn't within the talk", widget.SecondWithColons(th.left))
+		}
+	}
+	// The thresholds are crossed in the order of the time left.
+	sort.SliceStable(thresholds, func(i, j int) bool {
+		return thresholds[i].left > thresholds[j].left
+	})
+	return func(app *tview.Application) *tview.Application {
+		return TalkApp(app, seconds, thresholds)
+	}, nil
+}
+
+// thresholdColors returns the text and the background color of the
+// threshold i of n. They go from yellow, for the first, to red, for the
+// last.
+func thresholdColors(i, n int) (text, background tcell.Color) {
+	hue := 85.0
+	if n > 1 {
+		hue -= 70 * float64(i) / float64(n-1)
+	}
+	return tcell.GetColor(colorful.Hcl(hue, .6, .75).Hex()),
+		tcell.GetColor(colorful.Hcl(hue, .25, .15).Hex())
+}
+
+// TalkApp returns app after setting the root to time a talk of seconds,
+// which warns as it crosses each of thresholds, and then counts the
+// overtime.
+func TalkApp(app *tview.Application, seconds int, thresholds []threshold) *tview.Application {
+	t := newClock(widget.NewTimer(seconds))
+
+	// o counts the overtime once the talk's time has run out.
+	o := newClock(widget.NewStopwatch())
+
+	nextFormat := formatCycle(t.TotalSeconds, nil, t)
+	// setFormat sets the clock to the next format, and the overtime to
+	// the same one, after a plus sign.
+	var setFormat = func() {
+		tmpl := nextFormat()
+		format := t.Format
+		if tmpl != nil {
+			format = tmpl.Seconds
+		}
+		o.Format = func(seconds int) string {
+			return "+" + format(seconds)
+		}
+	}
+	setFormat()
+
+	// The speaker view shows the time elapsed, and left, and the
+	// progress, along with the clock.
+	p := widget.NewProgressBar()
+	p.SetStyle(int(barStyle))
+	markers := make([]float64, len(thresholds))
+	for i, th := range thresholds {
+		markers[i] = 1 - float64(th.left)/float64(seconds)
+	}
+	p.SetMarkers(markers...)
+	times := tview.NewTextView()
+	times.SetTextAlign(tview.AlignCenter)
+	status := tview.NewTextView()
+	status.SetTextAlign(tview.AlignCenter)
+
+	speaker := tview.NewFlex().SetDirection(tview.FlexRow)
+	clocks := tview.NewPages()
+	clocks.AddPage("timer", t, true, true)
+	clocks.AddPage("overtime", o, true, false)
+
+	// level is the count of the thresholds crossed, and overtime
+	// whether the time has run out.
+	level := 0
+	overtime := false
+
+	// setColors sets the text and the background color of the talk.
+	var setColors = func(text, background tcell.Color) {
+		t.TextColor, o.TextColor = text, text
+		speaker.SetBackgroundColor(background)
+		clocks.SetBackgroundColor(background)
+		t.SetBackgroundColor(background)
+		o.SetBackgroundColor(background)
+		p.TextColor = text
+		p.SetBackgroundColor(background)
+		times.SetBackgroundColor(background)
+		status.SetBackgroundColor(background)
+		status.SetTextColor(text)
+	}
+	// setLevel sets the colors and the status of the talk to those of
+	// level, and overtime.
+	var setLevel = func() {
+		switch {
+		case overtime:
+			setColors(ColorForeground, tcell.GetColor(colorful.Hcl(15, .7, .3).Hex()))
+			status.SetText("overtime")
+		case level == 0:
+			setColors(ColorForeground, ColorBackground)
+			status.SetText("")
+		default:
+			setColors(thresholdColors(level-1, len(thresholds)))
+			status.SetText(thresholds[level-1].name)
+		}
+	}
+
+	var update = func() {
+		elapsed := time.Duration(seconds)*time.Second - t.Value()
+		if overtime {
+			elapsed += o.Value()
+		}
+		left := t.Value()
+		if left < 0 {
+			left = 0
+		}
+		times.SetText(fmt.Sprintf("elapsed %s · left %s",
+			widget.SecondWithColons(int(elapsed/time.Second)),
+			widget.SecondWithColons(int(math.Ceil(left.Seconds())))))
+		progress := float64(elapsed) / float64(time.Duration(seconds)*time.Second)
+		p.SetProgress(math.Max(0, math.Min(1, progress)))
+	}
+	update()
+
+	t.Changed = func() {
+		app.QueueUpdateDraw(func() {
+			crossed := 0
+			for _, th := range thresholds {
+				if t.Value() <= time.Duration(th.left)*time.Second {
+					crossed++
+				}
+			}
+			if crossed > level {
+				// Each threshold chimes a little lower than the one
+				// before it.
+				playChimeAt(math.Pow(0.85, float64(crossed)))
+			}
+			if crossed != level {
+				level = crossed
+				setLevel()
+			}
+			update()
+		})
+	}
+	t.SetDoneFunc(func() {
+		go app.QueueUpdateDraw(func() {
+			overtime = true
+			o.SetElapsed(0)
+			o.Start()
+			clocks.SwitchToPage("overtime")
+			setLevel()
+			update()
+		})
+		playChimeAt(0.5)
+	})
+	o.Changed = func() {
+		app.QueueUpdateDraw(update)
+	}
+
+	type info struct {
+		km     widget.KeyMap
+		action func()
+	}
+	interactions := struct {
+		playpause, restart, view, format, quit info
+	}{
+		playpause: info{
+			km: widget.KeyMap{Key: "space", Desc: "play/pause"},
+		},
+		restart: info{
+			km: widget.KeyMap{Key: "r", Desc: "restart"},
+		},
+		view: info{
+			km: widget.KeyMap{Key: "v", Desc: "speaker view"},
+		},
+		format: info{
+			km: widget.KeyMap{Key: "f", Desc: "format"},
+		},
+		quit: info{
+			km: widget.KeyMap{Key: "q", Desc: "quit"},
+		},
+	}
+
+	interactions.playpause.action = func() {
+		c := t
+		if overtime {
+			c = o
+		}
+		if c.Running() {
+			c.Stop()
+		} else {
+			c.Start()
+		}
+	}
+	interactions.restart.action = func() {
+		o.Stop()
+		overtime, level = false, 0
+		clocks.SwitchToPage("timer")
+		setLevel()
+		t.Restart()
+	}
+	showSpeaker := true
+	interactions.view.action = func() {
+		showSpeaker = !showSpeaker
+		speaker.ResizeItem(times, 0, 0)
+		speaker.ResizeItem(p, 0, 0)
+		if showSpeaker {
+			speaker.ResizeItem(times, 1, 0)
+			speaker.ResizeItem(p, 0, 1)
+		}
+	}
+	interactions.format.action = func() {
+		setFormat()
+	}
+	interactions.quit.action = func() {
+		app.Stop()
+	}
+
+	hv := widget.NewHelpView([]widget.KeyMap{
+		interactions.playpause.km, interactions.restart.km,
+		interactions.view.km, interactions.format.km,
+		interactions.quit.km,
+	})
+	hv.SetDynamicColors(true)
+	hv.SetTextAlign(tview.AlignCenter)
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case ' ':
+				interactions.playpause.action()
+				return nil
+			case 'r':
+				interactions.restart.action()
+				return nil
+			case 'v':
+				interactions.view.action()
+				return nil
+			case 'f':
+				interactions.format.action()
+				return nil
+			case 'q':
+				interactions.quit.action()
+				return nil
+			}
+		}
+		return event
+	})
+
+	speaker.AddItem(status, 1, 0, false)
+	speaker.AddItem(clocks, 0, 3, false)
+	speaker.AddItem(times, 1, 0, false)
+	speaker.AddItem(p, 0, 1, false)
+	speaker.AddItem(hv, 2, 0, false)
+
+	t.SetVerticalAlign(widget.AlignCenter)
+	t.SetBorderPadding(1, 1, 2, 2)
+	o.SetVerticalAlign(widget.AlignCenter)
+	o.SetBorderPadding(1, 1, 2, 2)
+	p.SetAlign(widget.AlignCenter)
+	p.SetBorderPadding(0, 0, 2, 2)
+
+	SetTheme = func() {
+		setLevel()
+		t.ShadowColor = ColorShadow
+		o.ShadowColor = ColorShadow
+		p.ShadowColor = ColorShadow
+		times.SetTextColor(ColorSecondary)
+		hv.SetBackgroundColor(ColorBackground)
+		hv.SetKeyStyle(tcell.StyleDefault.Foreground(ColorSurface))
+		hv.SetDescStyle(tcell.StyleDefault.Foreground(ColorBorder))
+		hv.SetSeparatorStyle(tcell.StyleDefault.Foreground(ColorBorder))
+	}
+
+	t.Start()
+	return app.SetRoot(speaker, true)
+}
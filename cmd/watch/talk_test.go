@@ -0,0 +1,14 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+func TestTalkRestartAsDone(t *testing.T) {
+	app := TalkApp(tview.NewApplication(), 1, nil)
+	runApp(t, app)
+	pressAfter(t, app, 1500*time.Millisecond, 'r')
+}
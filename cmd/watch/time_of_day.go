@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ValenTheRed/watch/internal/widget"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// TimeOfDay parses the arguments of the clock command, and returns a
+// function that sets up app to show the time of day.
+func TimeOfDay(args []string) (func(app *tview.Application) *tview.Application, error) {
+	fs := newFlagSet("clock")
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		return nil, fmt.Errorf("clock takes no arguments")
+	}
+	return TimeOfDayApp, nil
+}
+
+// TimeOfDayApp returns app after setting the root to show the time of
+// day, on an analog dial if -analog is set.
+func TimeOfDayApp(app *tview.Application) *tview.Application {
+	c := newClock(widget.NewTimeOfDay())
+
+	a := widget.NewAnalogClock()
+
+	const dateLayout = "Monday, 02 January 2006"
+	day := time.Now().Format(dateLayout)
+	date := tview.NewTextView()
+	date.SetTextAlign(tview.AlignCenter)
+	date.SetText(day)
+
+	type info struct {
+		km     widget.KeyMap
+		action func()
+	}
+	interactions := struct {
+		quit, format info
+	}{
+		quit: info{
+			km: widget.KeyMap{Key: "q", Desc: "quit"},
+		},
+		format: info{
+			km: widget.KeyMap{Key: "f", Desc: "format"},
+		},
+	}
+
+	// The time of day, unlike a duration, always has it's hours, so
+	// only the templates, if any, are cycled through.
+	var formats []func() func(seconds int) string
+	format := 0
+	if len(templates.templates) > 0 {
+		formats = clockFormats(c, nil)
+		formats[format]()
+	}
+
+	interactions.quit.action = func() {
+		app.Stop()
+	}
+	interactions.format.action = func() {
+		if len(formats) == 0 {
+			return
+		}
+		format = (format + 1) % len(formats)
+		formats[format]()
+	}
+
+	hv := widget.NewHelpView([]widget.KeyMap{
+		interactions.format.km, interactions.quit.km,
+	})
+	hv.SetDynamicColors(true)
+	hv.SetTextAlign(tview.AlignCenter)
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'q':
+				interactions.quit.action()
+				return nil
+			case 'f':
+				interactions.format.action()
+				return nil
+			}
+		}
+		return event
+	})
+
+	root := tview.NewFlex().SetDirection(tview.FlexRow)
+	if analog {
+		root.AddItem(a, 0, 4, false)
+	} else {
+		root.AddItem(c, 0, 2, false)
+	}
+	root.AddItem(date, 0, 1, false)
+	root.AddItem(hv, 2, 1, false)
+
+	c.SetVerticalAlign(widget.AlignDown)
+	c.SetBorderPadding(1, 1, 2, 2)
+	a.SetBorderPadding(1, 0, 2, 2)
+	date.SetBorderPadding(1, 0, 2, 2)
+
+	SetTheme = func() {
+		c.SetBackgroundColor(ColorBackground)
+		a.SetBackgroundColor(ColorBackground)
+		date.SetBackgroundColor(ColorBackground)
+		hv.SetBackgroundColor(ColorBackground)
+		c.TextColor = ColorForeground
+		c.ShadowColor = ColorShadow
+		a.TextColor = ColorForeground
+		a.ShadowColor = ColorShadow
+		date.SetTextColor(ColorSecondary)
+		hv.SetKeyStyle(tcell.StyleDefault.Foreground(ColorSurface))
+		hv.SetDescStyle(tcell.StyleDefault.Foreground(ColorBorder))
+		hv.SetSeparatorStyle(tcell.StyleDefault.Foreground(ColorBorder))
+	}
+
+	// The clock follows the wall clock, running it only redraws the
+	// screen, the analog dial included, and keeps the date current.
+	c.Changed = func() {
+		if d := time.Now().Format(dateLayout); d != day {
+			day = d
+			app.QueueUpdateDraw(func() {
+				date.SetText(d)
+			})
+			return
+		}
+		app.Draw()
+	}
+	c.Start()
+	return app.SetRoot(root, true)
+}
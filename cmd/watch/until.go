@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ValenTheRed/watch/internal/widget"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// targetLayouts are the layouts, in local time unless they have a zone,
+// accepted for the target of until.
+var targetLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseTarget returns the time in s, which must be in one of
+// targetLayouts.
+func ParseTarget(s string) (time.Time, error) {
+	for _, layout := range targetLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date must be like 2026-12-31T23:59:59 or \"2026-11-03 09:00\"")
+}
+
+// Until parses the arguments of the until command, and returns a
+// function that sets up app to count down to the date and time in args.
+func Until(args []string) (func(app *tview.Application) *tview.Application, error) {
+	fs := newFlagSet("until")
+	fs.Parse(args)
+	// Allow the date and time to be given unquoted.
+	target, err := ParseTarget(strings.Join(fs.Args(), " "))
+	if err != nil {
+		return nil, err
+	}
+	return func(app *tview.Application) *tview.Application {
+		return UntilApp(app, target)
+	}, nil
+}
+
+// UntilApp returns app after setting the root and starting the count
+// down to target.
+func UntilApp(app *tview.Application, target time.Time) *tview.Application {
+	c := newClock(widget.NewUntil(target))
+
+	label := tview.NewTextView()
+	label.SetTextAlign(tview.AlignCenter)
+	var setLabel = func() {
+		when := "until"
+		if c.Passed() {
+			when = "since"
+		}
+		label.SetText(when + " " + target.Format("Mon, 02 Jan 2006 15:04:05"))
+	}
+	setLabel()
+
+	passed := c.Passed()
+	c.Changed = func() {
+		if !passed && c.Passed() {
+			passed = true
+			playChime()
+			app.QueueUpdateDraw(func() {
+				setLabel()
+				label.SetTextColor(ColorPrimary)
+			})
+			return
+		}
+		app.Draw()
+	}
+
+	type info struct {
+		km     widget.KeyMap
+		action func()
+	}
+	interactions := struct {
+		quit, format info
+	}{
+		quit: info{
+			km: widget.KeyMap{Key: "q", Desc: "quit"},
+		},
+		format: info{
+			km: widget.KeyMap{Key: "f", Desc: "format"},
+		},
+	}
+
+	// Keep the fields of the farthest the clock has been from target.
+	longest := 0
+	nextFormat := formatCycle(func() int {
+		if v := int(c.Value() / time.Second); v > longest {
+			longest = v
+		}
+		return longest
+	}, nil, c)
+	nextFormat()
+
+	interactions.quit.action = func() {
+		app.Stop()
+	}
+	interactions.format.action = func() {
+		nextFormat()
+	}
+
+	hv := widget.NewHelpView([]widget.KeyMap{
+		interactions.format.km, interactions.quit.km,
+	})
+	hv.SetDynamicColors(true)
+	hv.SetTextAlign(tview.AlignCenter)
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'q':
+				interactions.quit.action()
+				return nil
+			case 'f':
+				interactions.format.action()
+				return nil
+			}
+		}
+		return event
+	})
+
+	root := tview.NewFlex().SetDirection(tview.FlexRow)
+	root.AddItem(c, 0, 2, false)
+	root.AddItem(label, 0, 1, false)
+	root.AddItem(hv, 2, 1, false)
+
+	c.SetVerticalAlign(widget.AlignDown)
+	c.SetBorderPadding(1, 1, 2, 2)
+	label.SetBorderPadding(1, 0, 2, 2)
+
+	SetTheme = func() {
+		c.SetBackgroundColor(ColorBackground)
+		label.SetBackgroundColor(ColorBackground)
+		hv.SetBackgroundColor(ColorBackground)
+		c.TextColor = ColorForeground
+		c.ShadowColor = ColorShadow
+		label.SetTextColor(ColorSecondary)
+		if passed {
+			label.SetTextColor(ColorPrimary)
+		}
+		hv.SetKeyStyle(tcell.StyleDefault.Foreground(ColorSurface))
+		hv.SetDescStyle(tcell.StyleDefault.Foreground(ColorBorder))
+		hv.SetSeparatorStyle(tcell.StyleDefault.Foreground(ColorBorder))
+	}
+
+	c.Start()
+	return app.SetRoot(root, true)
+}
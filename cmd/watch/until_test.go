@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2026-12-31T23:59:59", time.Date(2026, 12, 31, 23, 59, 59, 0, time.Local), false},
+		{"2026-12-31T23:59", time.Date(2026, 12, 31, 23, 59, 0, 0, time.Local), false},
+		{"2026-11-03 09:00:30", time.Date(2026, 11, 3, 9, 0, 30, 0, time.Local), false},
+		{"2026-11-03 09:00", time.Date(2026, 11, 3, 9, 0, 0, 0, time.Local), false},
+		{"2026-11-03", time.Date(2026, 11, 3, 0, 0, 0, 0, time.Local), false},
+		// A zone, if given, is kept.
+		{"2026-12-31T23:59:59Z", time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC), false},
+		{"2026-12-31T23:59:59+05:30", time.Date(2026, 12, 31, 18, 29, 59, 0, time.UTC), false},
+		{"2026-13-01", time.Time{}, true},
+		{"31/12/2026", time.Time{}, true},
+		{"09:00", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTarget(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTarget(%q) error = %v, want error %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTarget(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package widget
+
+import (
+	"math"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// AnalogClock draws a round dial with Braille patterns. It either shows
+// the time with it's hands, or, for timers, a sweep that fills the dial
+// clockwise from twelve o'clock.
+type AnalogClock struct {
+	*tview.Box
+
+	// Both determine the alignment of the dial.
+	verticalAlign, horizontalAlign int
+
+	// TextColor is the color of the hands and the sweep.
+	TextColor tcell.Color
+
+	// ShadowColor is the color of the dial and the second hand.
+	ShadowColor tcell.Color
+
+	// sweep is the filled fraction of the dial, and belongs to the
+	// closed interval [0, 1]. It is negative when the dial shows the
+	// time instead.
+	sweep float64
+
+	// Now returns the time shown by the hands. It is time.Now by
+	// default.
+	Now func() time.Time
+}
+
+// NewAnalogClock returns a new AnalogClock that shows the current time,
+// with it's dial centered aligned both, vertically and horizontally.
+func NewAnalogClock() *AnalogClock {
+	return &AnalogClock{
+		Box:             tview.NewBox(),
+		verticalAlign:   AlignCenter,
+		horizontalAlign: tview.AlignCenter,
+		TextColor:       tcell.ColorWhite,
+		ShadowColor:     tcell.ColorGrey,
+		sweep:           -1,
+		Now:             time.Now,
+	}
+}
+
+// SetHorizontalAlign sets the horizontal alignment of the dial. Must be
+// one of tview.AlignCenter, tview.AlignLeft or tview.AlignRight.
+func (a *AnalogClock) SetHorizontalAlign(align int) *AnalogClock {
+	a.horizontalAlign = align
+	return a
+}
+
+// SetVerticalAlign sets the veritcal alignment of the dial. Must be one
+// of AlignCenter, AlignUp or AlignDown.
+func (a *AnalogClock) SetVerticalAlign(align int) *AnalogClock {
+	a.verticalAlign = align
+	return a
+}
+
+// SetSweep fills fraction v of the dial in place of the hands. v is
+// clamped to the closed interval [0, 1].
+func (a *AnalogClock) SetSweep(v float64) *AnalogClock {
+	a.sweep = math.Max(0, math.Min(1, v))
+	return a
+}
+
+// ShowTime makes the dial show the time with it's hands, in place of the
+// sweep.
+func (a *AnalogClock) ShowTime() *AnalogClock {
+	a.sweep = -1
+	return a
+}
+
+// dialPoint returns the dot at distance r from the center cx, cy, at
+// angle turns, measured clockwise from twelve o'clock in full turns.
+func dialPoint(cx, cy int, r, turns float64) (x, y int) {
+	angle := 2 * math.Pi * turns
+	return cx + int(math.Round(r*math.Sin(angle))), cy - int(math.Round(r*math.Cos(angle)))
+}
+
+func (a *AnalogClock) Draw(screen tcell.Screen) {
+	a.DrawForSubclass(screen, a)
+
+	x, y, width, height := a.GetInnerRect()
+
+	cv, c := newDial(width, height)
+	if cv == nil {
+		return
+	}
+	r := float64(c)
+
+	// The sweep.
+	if a.sweep >= 0 {
+		cv.fillDial(-1, r-1, a.sweep, dotText, dotNone)
+	}
+
+	// The dial and the hour marks.
+	steps := int(2 * math.Pi * r)
+	for i := 0; i < steps; i++ {
+		px, py := dialPoint(c, c, r, float64(i)/float64(steps))
+		cv.set(px, py, dotShadow)
+	}
+	for hour := 0; hour < 12; hour++ {
+		x0, y0 := dialPoint(c, c, r*0.85, float64(hour)/12)
+		x1, y1 := dialPoint(c, c, r, float64(hour)/12)
+		cv.line(x0, y0, x1, y1, dotShadow)
+	}
+
+	// The hands.
+	if a.sweep < 0 {
+		now := a.Now()
+		h, m, s := now.Clock()
+		second := float64(s) / 60
+		minute := (float64(m) + second) / 60
+		hour := (float64(h%12) + minute) / 12
+		var hand = func(turns, length float64, layer uint8) {
+			hx, hy := dialPoint(c, c, r*length, turns)
+			cv.line(c, c, hx, hy, layer)
+		}
+		hand(second, 0.8, dotShadow)
+		hand(minute, 0.75, dotText)
+		hand(hour, 0.5, dotText)
+	} else {
+		hx, hy := dialPoint(c, c, r, a.sweep)
+		cv.line(c, c, hx, hy, dotText)
+	}
+
+	w, h := cv.brailleSize()
+	if a.verticalAlign == AlignCenter {
+		y += getCenter(height, h)
+	} else if a.verticalAlign == AlignDown {
+		y += height - h
+	}
+	if a.horizontalAlign == tview.AlignCenter {
+		x += getCenter(width, w)
+	} else if a.horizontalAlign == tview.AlignRight {
+		x += width - w
+	}
+
+	background := a.GetBackgroundColor()
+	shadowStyle := tcell.StyleDefault.Foreground(a.ShadowColor).Background(background)
+	textStyle := tcell.StyleDefault.Foreground(a.TextColor).Background(background)
+	cv.drawBraille(screen, x, y, textStyle, shadowStyle)
+}
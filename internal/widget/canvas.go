@@ -0,0 +1,254 @@
+package widget
+
+import (
+	"math"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Layers a dot of a canvas can be lit by. A dot lit by a higher layer
+// isn't overwritten by a lower one.
+const (
+	dotNone uint8 = iota
+	dotShadow
+	dotText
+)
+
+// canvas is a grid of dots, each either unlit or lit by the text or the
+// shadow layer. It is drawn either with Braille patterns, which have
+// 2x4 dots per cell, or with half blocks, which have 1x2 dots per cell.
+type canvas struct {
+	width, height int
+	dots          [][]uint8
+}
+
+// newCanvas returns an unlit canvas of width x height dots.
+func newCanvas(width, height int) *canvas {
+	dots := make([][]uint8, height)
+	for i := range dots {
+		dots[i] = make([]uint8, width)
+	}
+	return &canvas{width: width, height: height, dots: dots}
+}
+
+// set lights the dot at x, y by layer, unless it is lit by a higher
+// layer. Dots outside of the canvas are ignored.
+func (cv *canvas) set(x, y int, layer uint8) {
+	if x < 0 || y < 0 || x >= cv.width || y >= cv.height {
+		return
+	}
+	if cv.dots[y][x] < layer {
+		cv.dots[y][x] = layer
+	}
+}
+
+// get returns the layer that lights the dot at x, y.
+func (cv *canvas) get(x, y int) uint8 {
+	if x < 0 || y < 0 || x >= cv.width || y >= cv.height {
+		return dotNone
+	}
+	return cv.dots[y][x]
+}
+
+// newDial returns a square canvas for a dial that fits within width x
+// height cells of Braille patterns, and it's center, or nil if the dial
+// would be too small to draw.
+func newDial(width, height int) (cv *canvas, center int) {
+	// A Braille dot is about as wide as it is tall, so a square of
+	// dots looks round.
+	diameter := min(2*width, 4*height)
+	if diameter < 8 {
+		return nil, 0
+	}
+	// Keep an odd diameter so that the dial has a center dot.
+	if diameter%2 == 0 {
+		diameter--
+	}
+	return newCanvas(diameter, diameter), diameter / 2
+}
+
+// fillDial lights the dots of cv, a canvas from newDial, more than inner
+// and at most outer dots from it's center. Those within sweep, the
+// fraction of a full turn clockwise from twelve o'clock, are lit by
+// swept and the rest by unswept.
+func (cv *canvas) fillDial(inner, outer, sweep float64, swept, unswept uint8) {
+	c := cv.width / 2
+	for dy := -c; dy <= c; dy++ {
+		for dx := -c; dx <= c; dx++ {
+			d := math.Hypot(float64(dx), float64(dy))
+			if d > outer || d <= inner {
+				continue
+			}
+			// Angle clockwise from twelve o'clock in full turns.
+			turns := math.Atan2(float64(dx), float64(-dy)) / (2 * math.Pi)
+			if turns < 0 {
+				turns++
+			}
+			layer := unswept
+			if turns < sweep || sweep == 1 {
+				layer = swept
+			}
+			cv.set(c+dx, c+dy, layer)
+		}
+	}
+}
+
+// line lights the dots on the line from x0, y0 to x1, y1 by layer.
+func (cv *canvas) line(x0, y0, x1, y1 int, layer uint8) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	// Bresenham's line algorithm.
+	err := dx + dy
+	for {
+		cv.set(x0, y0, layer)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// textCanvas returns a canvas of the pixels of text, which is taken to
+// be drawn with full and half blocks; every cell is two pixels tall and
+// one wide. Other characters are ignored. Every pixel casts a shadow
+// one pixel to the right and below it.
+func textCanvas(text []string) *canvas {
+	cv := newCanvas(textWidth(text)+1, 2*len(text)+1)
+	for y, s := range text {
+		x := 0
+		for _, r := range s {
+			top := r == '█' || r == '▀'
+			bottom := r == '█' || r == '▄'
+			if top {
+				cv.set(x, 2*y, dotText)
+				cv.set(x+1, 2*y+1, dotShadow)
+			}
+			if bottom {
+				cv.set(x, 2*y+1, dotText)
+				cv.set(x+1, 2*y+2, dotShadow)
+			}
+			x++
+		}
+	}
+	// Drop the unlit rows at the bottom, such as those of the glyphs'
+	// descenders, to save space.
+	for cv.height > 0 && isUnlit(cv.dots[cv.height-1]) {
+		cv.height--
+		cv.dots = cv.dots[:cv.height]
+	}
+	return cv
+}
+
+// isUnlit returns whether none of dots is lit.
+func isUnlit(dots []uint8) bool {
+	for _, d := range dots {
+		if d != dotNone {
+			return false
+		}
+	}
+	return true
+}
+
+// brailleSize returns the size in cells of cv drawn with Braille
+// patterns.
+func (cv *canvas) brailleSize() (width, height int) {
+	return (cv.width + 1) / 2, (cv.height + 3) / 4
+}
+
+// halfBlockSize returns the size in cells of cv drawn with half blocks.
+func (cv *canvas) halfBlockSize() (width, height int) {
+	return cv.width, (cv.height + 1) / 2
+}
+
+// brailleBits are the bits of the Braille pattern for the dots of a
+// cell, indexed by [y][x].
+var brailleBits = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// drawBraille draws cv on screen, from x, y, with Braille patterns. A
+// cell with any dot lit by the text layer is drawn in textStyle, others
+// in shadowStyle. Unlit cells are skipped.
+func (cv *canvas) drawBraille(screen tcell.Screen, x, y int, textStyle, shadowStyle tcell.Style) {
+	width, height := cv.brailleSize()
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			var pattern rune
+			layer := dotNone
+			for dy := 0; dy < 4; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					if l := cv.get(2*col+dx, 4*row+dy); l != dotNone {
+						pattern |= brailleBits[dy][dx]
+						if l > layer {
+							layer = l
+						}
+					}
+				}
+			}
+			if layer == dotNone {
+				continue
+			}
+			style := shadowStyle
+			if layer == dotText {
+				style = textStyle
+			}
+			screen.SetContent(x+col, y+row, 0x2800+pattern, nil, style)
+		}
+	}
+}
+
+// drawHalfBlocks draws cv on screen, from x, y, with half blocks. Since
+// a cell has a foreground and a background color, both of it's dots
+// keep their own color, text or shadow. Unlit dots are drawn in
+// background.
+func (cv *canvas) drawHalfBlocks(screen tcell.Screen, x, y int, text, shadow, background tcell.Color) {
+	var color = func(layer uint8) tcell.Color {
+		switch layer {
+		case dotText:
+			return text
+		case dotShadow:
+			return shadow
+		}
+		return background
+	}
+	width, height := cv.halfBlockSize()
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			top, bottom := cv.get(col, 2*row), cv.get(col, 2*row+1)
+			style := tcell.StyleDefault.Background(background)
+			r := ' '
+			switch {
+			case top == dotNone && bottom == dotNone:
+			case top == dotNone:
+				r, style = '▄', style.Foreground(color(bottom))
+			default:
+				r, style = '▀', style.Foreground(color(top)).Background(color(bottom))
+			}
+			screen.SetContent(x+col, y+row, r, nil, style)
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,28 @@
+package widget
+
+import "testing"
+
+func TestFillDial(t *testing.T) {
+	cv, c := newDial(10, 5)
+	if cv == nil || cv.width != 19 || c != 9 {
+		t.Fatalf("newDial(10, 5) = %v, %d, want a 19 dot dial centered at 9", cv, c)
+	}
+	cv.fillDial(-1, float64(c), 0.5, dotText, dotShadow)
+	tests := []struct {
+		x, y int
+		want uint8
+	}{
+		{c, c, dotText},     // the center
+		{c + 5, c, dotText}, // three o'clock
+		{c - 5, c, dotShadow},
+		{0, 0, dotNone}, // outside of the dial
+	}
+	for _, tt := range tests {
+		if got := cv.get(tt.x, tt.y); got != tt.want {
+			t.Errorf("dot at %d, %d = %d, want %d", tt.x, tt.y, got, tt.want)
+		}
+	}
+	if cv, _ := newDial(3, 1); cv != nil {
+		t.Errorf("newDial(3, 1) = %v, want nil", cv)
+	}
+}
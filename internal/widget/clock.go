@@ -2,12 +2,29 @@ package widget
 
 import (
 	"math"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/mattn/go-runewidth"
 	"github.com/rivo/tview"
 )
 
+// Compact renderings of Clock.
+const (
+	// CompactNone draws the text in Font.
+	CompactNone = iota
+
+	// CompactHalfBlock draws the pixels of the text in CompactFont with
+	// half blocks, giving every pixel and it's shadow their own color.
+	CompactHalfBlock
+
+	// CompactBraille draws the pixels of the text in CompactFont with
+	// Braille patterns.
+	CompactBraille
+)
+
 type Clock struct {
 	*tview.Box
 
@@ -17,12 +34,28 @@ type Clock struct {
 	// elapsed is the time passed in seconds.
 	elapsed int
 
+	// partial is the time passed towards the next second.
+	partial time.Duration
+
+	// countdown is whether the clock's value counts down.
+	countdown bool
+
+	// target is the time that a Clock made by NewUntil counts to.
+	target time.Time
+
+	// wall, if set, returns the value of a Clock that follows the wall
+	// clock instead of it's ticks.
+	wall func() time.Duration
+
 	// Both determine the alignment of the clock text.
 	verticalAlign, horizontalAlign int
 
 	// stopCh will be used to signal to clock to stop ticking.
 	stopCh chan struct{}
 
+	// workerDone is closed once the ticking started last has returned.
+	workerDone chan struct{}
+
 	// TextColor is the text color clock.
 	TextColor tcell.Color
 
@@ -33,8 +66,39 @@ type Clock struct {
 	// finishes.
 	done func()
 
-	// Format returns Clock value in ANSI Shadow font.
-	Format func(second int) []string
+	// Format returns Clock value as text, which is then drawn in Font.
+	Format func(second int) string
+
+	// template, if set, is used to format the Clock value instead of
+	// Format.
+	template *Template
+
+	// Font is the font Clock's text is drawn in.
+	Font *Font
+
+	// Fallbacks are the fonts, in order, that Clock's text is drawn in
+	// when it doesn't fit in Font. If the text doesn't fit in any of
+	// them either, then it is drawn as plain text.
+	Fallbacks []*Font
+
+	// Compact is the compact rendering of the text, one of
+	// CompactNone, CompactHalfBlock and CompactBraille. If the compact
+	// rendering doesn't fit, then the text is drawn as it would be with
+	// CompactNone.
+	Compact int
+
+	// CompactFont is the font whose full and half blocks are the pixels
+	// of the compact renderings.
+	CompactFont *Font
+
+	// TabularFigures pads every digit to the same width, so that the
+	// text doesn't shift sideways as the digits change.
+	TabularFigures bool
+
+	// MaxScale is the largest factor by which the text, drawn in a
+	// scalable font, is scaled up when there is room for it. Scaling is
+	// disabled for values less than 2.
+	MaxScale int
 
 	// value will be used by Format to generate the text of Clock to
 	// draw.
@@ -54,7 +118,8 @@ type Clock struct {
 
 // newClock returns a new Clock. It has horizontal and vertical aligment
 // set to center, stopCh is uninitialised, value is the elapsed seconds,
-// and Format is SecondToANSIShadowWithColons.
+// Format is SecondWithColons, Font is ANSIShadow and it falls back to
+// ANSIRegular and Mini. The compact renderings are off, and use Mini.
 func newClock() *Clock {
 	c := &Clock{
 		Box:             tview.NewBox(),
@@ -62,51 +127,121 @@ func newClock() *Clock {
 		horizontalAlign: tview.AlignCenter,
 		TextColor:       tcell.ColorWhite,
 		ShadowColor:     tcell.ColorGrey,
+		Font:            ANSIShadow,
+		Fallbacks:       []*Font{ANSIRegular, Mini},
+		MaxScale:        4,
+		CompactFont:     Mini,
 	}
 	c.value = func() int {
 		return c.elapsed
 	}
-	c.Format = SecondToANSIShadowWithColons
+	c.Format = SecondWithColons
 	return c
 }
 
 // NewTimer returns an initialised Clock that behaves like a timer. It
 // counts down for duration seconds, and has it's text centered aligned
-// both, vertically and horizontally. It uses
-// SecondToANSIShadowWithLetters to format it's value.
+// both, vertically and horizontally. It uses SecondWithLetters to
+// format it's value.
 func NewTimer(duration int) *Clock {
 	c := newClock()
 	c.total = duration
-	// Timer will tick every second using Worker(). stopCh will be
-	// used as the quit channel for Worker() and will be called from
-	// the work() function being executed by Worker(). Since work()
-	// is not executed in a go routine, signaling quit/stopCh
-	// channel from work() would lead to a deadlock.
+	// Timer will tick using WorkerEvery(). stopCh will be used as
+	// the quit channel for WorkerEvery() and will be called from the
+	// work function being executed by WorkerEvery(). Since the work
+	// function is not executed in a go routine, signaling quit/stopCh
+	// channel from it would lead to a deadlock.
 	c.stopCh = make(chan struct{}, 1)
+	c.countdown = true
 	c.value = func() int {
 		return c.total - c.elapsed
 	}
-	c.Format = SecondToANSIShadowWithLetters
+	c.Format = SecondWithLetters
 	return c
 }
 
 // NewStopwatch returns an initialised Clock that behaves like a
 // stopwatch. It has it's text centered aligned both, vertically and
-// horizontally. It uses SecondToANSIShadowWithLetters to format it's
-// value.
+// horizontally. It uses SecondWithLetters to format it's value.
 func NewStopwatch() *Clock {
 	c := newClock()
 	c.total = math.MaxInt
-	// Stopwatch will never call Stop() from Worker(), so we don't need
-	// stopCh to be buffered.
+	// Stopwatch will never call Stop() from WorkerEvery(), so we don't
+	// need stopCh to be buffered.
 	c.stopCh = make(chan struct{})
 	c.value = func() int {
 		return c.elapsed
 	}
-	c.Format = SecondToANSIShadowWithLetters
+	c.Format = SecondWithLetters
+	return c
+}
+
+// NewUntil returns an initialised Clock that counts down to target and,
+// once it has passed, counts up since target. It's value follows the
+// wall clock, so it keeps time even when stopped; running it only keeps
+// it's text up to date. It uses SecondWithLetters to format it's value.
+func NewUntil(target time.Time) *Clock {
+	c := NewStopwatch()
+	c.target = target
+	c.wall = func() time.Duration {
+		if d := time.Until(c.target); d > 0 {
+			return d
+		}
+		return time.Since(c.target)
+	}
+	c.value = func() int {
+		d := c.wall()
+		if !c.Passed() {
+			// Round up, so that the clock reads 0 only once target
+			// has passed.
+			return int((d + time.Second - 1) / time.Second)
+		}
+		return int(d / time.Second)
+	}
 	return c
 }
 
+// NewTimeOfDay returns an initialised Clock whose value is the time of
+// day, in seconds since midnight. Like NewUntil, it follows the wall
+// clock. It formats it's value as 'XX:XX:XX'.
+func NewTimeOfDay() *Clock {
+	c := NewStopwatch()
+	c.wall = func() time.Duration {
+		now := time.Now()
+		h, m, s := now.Clock()
+		return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+			time.Duration(s)*time.Second + time.Duration(now.Nanosecond())
+	}
+	c.value = func() int {
+		return int(c.wall() / time.Second)
+	}
+	c.Format = func(second int) string {
+		return SecondWithColonsFixed(second, 3600)
+	}
+	return c
+}
+
+// SetTarget sets the target of a Clock made by NewUntil to target.
+func (c *Clock) SetTarget(target time.Time) *Clock {
+	c.target = target
+	if c.Changed != nil {
+		go c.Changed()
+	}
+	return c
+}
+
+// Passed returns whether the target of a Clock made by NewUntil has
+// passed.
+func (c *Clock) Passed() bool {
+	return !c.target.IsZero() && !time.Now().Before(c.target)
+}
+
+// countingDown returns whether c counts down, like a timer, or a count
+// down to a target that hasn't passed.
+func (c *Clock) countingDown() bool {
+	return c.countdown || (!c.target.IsZero() && !c.Passed())
+}
+
 // Running returns Clock status i.e. currently running or not.
 func (c *Clock) Running() bool {
 	return c.running
@@ -122,6 +257,26 @@ func (c *Clock) ElapsedSeconds() int {
 	return c.elapsed
 }
 
+// Value returns the Clock value, including the time passed towards the
+// next second.
+func (c *Clock) Value() time.Duration {
+	if c.wall != nil {
+		return c.wall()
+	}
+	v := time.Duration(c.value()) * time.Second
+	if c.countdown {
+		return v - c.partial
+	}
+	return v + c.partial
+}
+
+// SetTemplate sets t as the format of the Clock value, in place of
+// Format. A nil t restores Format.
+func (c *Clock) SetTemplate(t *Template) *Clock {
+	c.template = t
+	return c
+}
+
 // SetHorizontalAlign sets the veritcal alignment of the text. Must be
 // one of tview.AlignCenter, tview.AlignLeft or tview.AlignRight.
 func (c *Clock) SetHorizontalAlign(align int) *Clock {
@@ -137,7 +292,10 @@ func (c *Clock) SetVerticalAlign(align int) *Clock {
 }
 
 // SetDoneFunc sets a handler which is called when the clock has
-// finished.
+// finished. It is called from the clock's ticking, which Start waits
+// for, so it mustn't start the clock again, nor wait on anything that
+// may, like tview.Application.QueueUpdate. It may queue that up from
+// another goroutine instead.
 func (c *Clock) SetDoneFunc(handler func()) *Clock {
 	c.done = handler
 	return c
@@ -152,6 +310,24 @@ func (c *Clock) IsTimeLeft() bool {
 // SetElapsed sets the Clock's elapsed seconds to sec.
 func (c *Clock) SetElapsed(sec int) *Clock {
 	c.elapsed = sec
+	c.partial = 0
+	if c.Changed != nil {
+		go c.Changed()
+	}
+	return c
+}
+
+// Rewind takes d off the time the Clock has passed, which gives a timer
+// d more time left. The time passed may go below 0, so that a timer can
+// have more time left than it started with.
+func (c *Clock) Rewind(d time.Duration) *Clock {
+	passed := time.Duration(c.elapsed)*time.Second + c.partial - d
+	c.elapsed = int(passed / time.Second)
+	c.partial = passed % time.Second
+	if c.partial < 0 {
+		c.elapsed--
+		c.partial += time.Second
+	}
 	if c.Changed != nil {
 		go c.Changed()
 	}
@@ -164,13 +340,44 @@ func (c *Clock) SetTotalDuration(sec int) *Clock {
 	return c
 }
 
-// Start starts the clock if time is left.
+// Start starts the clock if time is left. It waits for the ticking
+// started before, if any, to return first, so that it can't take the
+// stop signal meant for the new one. So, it mustn't be called from the
+// done function, which the ticking calls, nor while the done function
+// waits on the caller.
 func (c *Clock) Start() *Clock {
 	if c.running || !c.IsTimeLeft() {
 		return c
 	}
+	if c.workerDone != nil {
+		<-c.workerDone
+	}
 	c.running = true
-	go Worker(func() {
+	c.workerDone = make(chan struct{})
+	go c.work(c.workerDone)
+	if c.Started != nil {
+		c.Started()
+	}
+	return c
+}
+
+// clockTick is the interval at which Clock ticks.
+const clockTick = time.Second / 10
+
+// work ticks the clock until it is stopped. Changed is fired between
+// seconds only if the template shows fractions of a second, or the
+// clock follows the wall clock, whose seconds needn't line up with the
+// clock's ticks. done is closed once it returns.
+func (c *Clock) work(done chan struct{}) {
+	defer close(done)
+	WorkerEvery(clockTick, func() {
+		if c.partial += clockTick; c.partial < time.Second {
+			fraction := c.template != nil && c.template.HasFraction()
+			if c.Changed != nil && (fraction || c.wall != nil) {
+				go c.Changed()
+			}
+			return
+		}
 		c.SetElapsed(c.elapsed + 1)
 		if c.IsTimeLeft() {
 			return
@@ -180,10 +387,6 @@ func (c *Clock) Start() *Clock {
 			c.done()
 		}
 	}, c.stopCh)
-	if c.Started != nil {
-		c.Started()
-	}
-	return c
 }
 
 // Stop signals clock to stop ticking.
@@ -206,31 +409,160 @@ func (c *Clock) Restart() *Clock {
 	return c
 }
 
+// render returns str in the largest rendering that fits within width
+// and height. Scaled up renderings of Font are tried first, or, if Font
+// can't be scaled, of the scalable font of it's design, if any. Then
+// Font and Fallbacks, and at last the plain text. Fonts that don't
+// define every character of str are skipped.
+//
+// Whether a rendering fits is decided using the widest digit of the
+// font in place of every digit, so that the rendering doesn't change
+// as the digits change.
+func (c *Clock) render(str string, width, height int) []string {
+	var fits = func(f *Font, scale int) bool {
+		widest := strings.Map(func(r rune) rune {
+			if unicode.IsDigit(r) {
+				return f.widestDigit()
+			}
+			return r
+		}, str)
+		text := f.Render(widest)
+		return len(text)*scale <= height && textWidth(text)*scale <= width
+	}
+	// usable returns f, with tabular figures if set, or nil if f
+	// doesn't define every character of str.
+	var usable = func(f *Font) *Font {
+		if f == nil || !f.Defines(str) {
+			return nil
+		}
+		if c.TabularFigures {
+			f = f.Tabular()
+		}
+		return f
+	}
+
+	grown := c.Font
+	if !grown.Scalable() {
+		grown = grown.scaled
+	}
+	if f := usable(grown); f != nil && f.Scalable() {
+		for scale := c.MaxScale; scale > 1; scale-- {
+			if fits(f, scale) {
+				return ScaleText(f.Render(str), scale)
+			}
+		}
+	}
+	for _, f := range append([]*Font{c.Font}, c.Fallbacks...) {
+		if f = usable(f); f != nil && fits(f, 1) {
+			return f.Render(str)
+		}
+	}
+	if runewidth.StringWidth(str) > width {
+		return []string{strings.ReplaceAll(str, " ", "")}
+	}
+	return []string{str}
+}
+
+// drawCompact draws str in the compact rendering, and returns whether it
+// did, which it doesn't if the rendering doesn't fit.
+func (c *Clock) drawCompact(screen tcell.Screen, str string) bool {
+	f := c.CompactFont
+	if c.TabularFigures {
+		f = f.Tabular()
+	}
+	if !f.Defines(str) {
+		return false
+	}
+	cv := textCanvas(f.Render(str))
+
+	w, h := cv.halfBlockSize()
+	if c.Compact == CompactBraille {
+		w, h = cv.brailleSize()
+	}
+	x, y, width, height := c.GetInnerRect()
+	if w > width || h > height {
+		return false
+	}
+	if c.verticalAlign == AlignCenter {
+		y += getCenter(height, h)
+	} else if c.verticalAlign == AlignDown {
+		y += height - h
+	}
+	if c.horizontalAlign == tview.AlignCenter {
+		x += getCenter(width, w)
+	} else if c.horizontalAlign == tview.AlignRight {
+		x += width - w
+	}
+
+	background := c.GetBackgroundColor()
+	if c.Compact == CompactBraille {
+		shadowStyle := tcell.StyleDefault.Foreground(c.ShadowColor).Background(background)
+		textStyle := tcell.StyleDefault.Foreground(c.TextColor).Background(background)
+		cv.drawBraille(screen, x, y, textStyle, shadowStyle)
+	} else {
+		cv.drawHalfBlocks(screen, x, y, c.TextColor, c.ShadowColor, background)
+	}
+	return true
+}
+
+// isShadowRune returns whether r is drawn with the shadow color, which
+// is the case for the box drawing characters.
+func isShadowRune(r rune) bool {
+	return r >= '─' && r <= '╿'
+}
+
+// text returns the value of c formatted with the template, if set, or
+// Format.
+func (c *Clock) text() string {
+	if c.template == nil {
+		return c.Format(c.value())
+	}
+	v := c.Value()
+	// Without fractions of a second, a count down is rounded up to the
+	// second, like value, so that it reads 0 only once it runs out.
+	if !c.template.HasFraction() && c.countingDown() {
+		v = time.Duration(c.value()) * time.Second
+	}
+	return c.template.Format(v)
+}
+
 func (c *Clock) Draw(screen tcell.Screen) {
 	c.DrawForSubclass(screen, c)
 
-	text := c.Format(c.value())
+	left, top, width, height := c.GetInnerRect()
+	str := c.text()
+	if c.Compact != CompactNone && c.drawCompact(screen, str) {
+		return
+	}
+	text := c.render(str, width, height)
 
-	x, y, width, height := c.GetInnerRect()
+	// Text that doesn't fit is clipped on the right and bottom.
+	x, y := left, top
 	if c.verticalAlign == AlignCenter {
-		y += getCenter(height, len(text))
+		y += max(0, getCenter(height, len(text)))
 	} else if c.verticalAlign == AlignDown {
-		y += height - len(text)
+		y += max(0, height-len(text))
 	}
 	if c.horizontalAlign == tview.AlignCenter {
-		x += getCenter(width, runewidth.StringWidth(text[0]))
+		x += max(0, getCenter(width, textWidth(text)))
 	} else if c.horizontalAlign == tview.AlignRight {
-		x += width - runewidth.StringWidth(text[0])
+		x += max(0, width-textWidth(text))
 	}
 
 	shadowStyle := tcell.StyleDefault.Foreground(c.ShadowColor).Background(c.GetBackgroundColor())
 	textStyle := tcell.StyleDefault.Foreground(c.TextColor).Background(c.GetBackgroundColor())
 
 	for _, s := range text {
+		if y >= top+height {
+			break
+		}
 		i := 0
 		for _, r := range s {
+			if x+i >= left+width {
+				break
+			}
 			style := textStyle
-			if r != '█' {
+			if r == ' ' || isShadowRune(r) {
 				style = shadowStyle
 			}
 			screen.SetContent(x+i, y, r, nil, style)
@@ -0,0 +1,74 @@
+package widget
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClockRestartAfterDone(t *testing.T) {
+	c := NewTimer(1)
+	done := make(chan struct{}, 1)
+	c.SetDoneFunc(func() {
+		done <- struct{}{}
+	})
+	c.Start()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timer didn't finish")
+	}
+
+	// Restarting from outside of the done function waits for the
+	// ticking that finished to return, which leaves only the new one
+	// to stop.
+	c.Restart()
+	c.Stop()
+	passed := c.Value()
+	time.Sleep(3 * clockTick)
+	if c.Running() || c.Value() != passed {
+		t.Errorf("clock ticked on after being stopped: %v, then %v", passed, c.Value())
+	}
+}
+
+func TestClockRender(t *testing.T) {
+	// user is a font of 2 rows, as it would be loaded with -font, that
+	// defines the characters up to the colon as themselves.
+	var src strings.Builder
+	src.WriteString("flf2a$ 2 1 4 0 0\n")
+	for _, r := range requiredCharacters {
+		src.WriteString(string(r) + string(r) + "@\n" + string(r) + string(r) + "@@\n")
+		if r == ':' {
+			break
+		}
+	}
+	user, err := ParseFont(strings.NewReader(src.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		font          *Font
+		width, height int
+		want          []string
+	}{
+		// A font that can't be scaled isn't replaced by a scaled one,
+		// however much room there is.
+		{"user font", user, 200, 60, user.Render("12:34")},
+		{"user font, small", user, 10, 2, user.Render("12:34")},
+		{"scalable font", ANSIRegular, 200, 60, ScaleText(ANSIRegular.Render("12:34"), 4)},
+		// ANSI Shadow grows in ANSI Regular, it's scalable design.
+		{"default font", ANSIShadow, 200, 60, ScaleText(ANSIRegular.Render("12:34"), 4)},
+		{"default font, fits", ANSIShadow, 60, 6, ANSIShadow.Render("12:34")},
+		{"plain text", user, 5, 1, []string{"12:34"}},
+	}
+	for _, tt := range tests {
+		c := NewStopwatch()
+		c.Font = tt.font
+		if got := c.render("12:34", tt.width, tt.height); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: render() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package widget
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+//go:embed fonts/*.flf
+var embeddedFonts embed.FS
+
+// The embedded fonts.
+var (
+	// ANSIShadow is the ANSI Shadow font, and the default font of Clock.
+	ANSIShadow = mustLoadEmbeddedFont("ansi-shadow")
+
+	// ANSIRegular is a five row font made of only full blocks, and
+	// hence can be scaled.
+	ANSIRegular = mustLoadEmbeddedFont("ansi-regular")
+
+	// Mini is a three row font made of half blocks.
+	Mini = mustLoadEmbeddedFont("mini")
+)
+
+// Font is a FIGlet font. Characters are rendered at full width, that
+// is, without any kerning or smushing.
+type Font struct {
+	// Name of the font.
+	Name string
+
+	// Height is the number of rows of every character.
+	Height int
+
+	// glyphs maps a character to it's rows. All rows of a glyph are
+	// of equal width.
+	glyphs map[rune][][]rune
+
+	// scalable is whether every glyph is made of only full blocks and
+	// spaces.
+	scalable bool
+
+	// scaled is the scalable font of the same design, if any, that
+	// text is drawn in when it is scaled up.
+	scaled *Font
+
+	// tabular caches the font returned by Tabular.
+	tabular *Font
+}
+
+// FontNames returns the names of the embedded fonts.
+func FontNames() []string {
+	entries, _ := embeddedFonts.ReadDir("fonts")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".flf"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadFont returns the embedded font called name. If there is no such
+// font, name is treated as the path to a FIGlet font file.
+func LoadFont(name string) (*Font, error) {
+	if f, err := loadEmbeddedFont(name); err == nil {
+		return f, nil
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("font: %q is neither an embedded font (%s) nor a readable file: %v",
+			name, strings.Join(FontNames(), ", "), err)
+	}
+	defer file.Close()
+	f, err := ParseFont(file)
+	if err != nil {
+		return nil, err
+	}
+	f.Name = strings.TrimSuffix(path.Base(name), ".flf")
+	return f, nil
+}
+
+// scaledFonts maps the embedded fonts that can't be scaled to the
+// embedded font of the same design that can.
+var scaledFonts = map[string]string{
+	"ansi-shadow": "ansi-regular",
+}
+
+func loadEmbeddedFont(name string) (*Font, error) {
+	file, err := embeddedFonts.Open("fonts/" + name + ".flf")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	f, err := ParseFont(file)
+	if err != nil {
+		return nil, err
+	}
+	f.Name = name
+	if scaled, ok := scaledFonts[name]; ok {
+		if f.scaled, err = loadEmbeddedFont(scaled); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func mustLoadEmbeddedFont(name string) *Font {
+	f, err := loadEmbeddedFont(name)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// requiredCharacters are the characters, in order, that every FIGlet
+// font must define before any code tagged characters.
+var requiredCharacters = func() []rune {
+	var rs []rune
+	for r := rune(32); r <= 126; r++ {
+		rs = append(rs, r)
+	}
+	return append(rs, 196, 214, 220, 228, 246, 252, 223)
+}()
+
+// ParseFont parses a FIGlet font (.flf) from r. Hardblanks are replaced
+// by spaces. A font that ends before defining all of the required
+// characters is accepted, and characters it doesn't define are not
+// rendered.
+func ParseFont(r io.Reader) (*Font, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+
+	if !sc.Scan() {
+		return nil, fmt.Errorf("font: missing header")
+	}
+	header := strings.Fields(sc.Text())
+	if len(header) < 6 || !strings.HasPrefix(header[0], "flf2a") || len(header[0]) < 6 {
+		return nil, fmt.Errorf("font: invalid header %q", sc.Text())
+	}
+	hardblank := []rune(header[0])[5]
+	height, err := strconv.Atoi(header[1])
+	if err != nil || height < 1 {
+		return nil, fmt.Errorf("font: invalid height %q", header[1])
+	}
+	comments, err := strconv.Atoi(header[5])
+	if err != nil || comments < 0 {
+		return nil, fmt.Errorf("font: invalid comment line count %q", header[5])
+	}
+	for i := 0; i < comments; i++ {
+		if !sc.Scan() {
+			return nil, fmt.Errorf("font: unexpected end of comments")
+		}
+	}
+
+	f := &Font{Height: height, glyphs: make(map[rune][][]rune)}
+
+	// readGlyph reads the next height lines as a glyph. ok is false
+	// when there are no lines left.
+	var readGlyph = func() (glyph [][]rune, ok bool, err error) {
+		glyph = make([][]rune, height)
+		for i := range glyph {
+			if !sc.Scan() {
+				if i == 0 {
+					return nil, false, sc.Err()
+				}
+				return nil, false, fmt.Errorf("font: unexpected end of character")
+			}
+			glyph[i] = trimEndmark([]rune(sc.Text()))
+			for j, r := range glyph[i] {
+				if r == hardblank {
+					glyph[i][j] = ' '
+				}
+			}
+		}
+		return padGlyph(glyph), true, nil
+	}
+
+	for _, r := range requiredCharacters {
+		glyph, ok, err := readGlyph()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		if len(glyph[0]) > 0 {
+			f.glyphs[r] = glyph
+		}
+	}
+
+	// Code tagged characters.
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		code, err := strconv.ParseInt(fields[0], 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("font: invalid character code %q", fields[0])
+		}
+		glyph, ok, err := readGlyph()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("font: missing character %d", code)
+		}
+		// Negative codes are not characters.
+		if code >= 0 {
+			f.glyphs[rune(code)] = glyph
+		}
+	}
+	f.scalable = isScalable(f.glyphs)
+	return f, sc.Err()
+}
+
+// isScalable returns whether glyphs are made of only full blocks and
+// spaces.
+func isScalable(glyphs map[rune][][]rune) bool {
+	for _, g := range glyphs {
+		for _, row := range g {
+			for _, r := range row {
+				if r != '█' && r != ' ' {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// trimEndmark removes trailing whitespace and the endmark characters
+// from a line of a FIGlet character. The endmark is the last character
+// of the line, and may be repeated.
+func trimEndmark(line []rune) []rune {
+	k := len(line) - 1
+	for k >= 0 && (line[k] == ' ' || line[k] == '\t' || line[k] == '\r') {
+		k--
+	}
+	if k >= 0 {
+		endmark := line[k]
+		for k >= 0 && line[k] == endmark {
+			k--
+		}
+	}
+	return line[:k+1]
+}
+
+// padGlyph pads all rows of glyph with spaces to the width of it's
+// widest row.
+func padGlyph(glyph [][]rune) [][]rune {
+	width := 0
+	for _, row := range glyph {
+		width = max(width, len(row))
+	}
+	for i, row := range glyph {
+		for len(row) < width {
+			row = append(row, ' ')
+		}
+		glyph[i] = row
+	}
+	return glyph
+}
+
+// Glyph returns the rows of character r, and whether f defines r.
+func (f *Font) Glyph(r rune) ([][]rune, bool) {
+	g, ok := f.glyphs[r]
+	return g, ok
+}
+
+// Defines returns whether f defines every character of str.
+func (f *Font) Defines(str string) bool {
+	for _, r := range str {
+		if _, ok := f.glyphs[r]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Render returns str in font f. Characters not defined by f are
+// skipped.
+func (f *Font) Render(str string) []string {
+	text := make([]string, f.Height)
+	for i := range text {
+		rs := []rune{}
+		for _, r := range str {
+			if g, ok := f.glyphs[r]; ok {
+				rs = append(rs, g[i]...)
+			}
+		}
+		text[i] = string(rs)
+	}
+	return text
+}
+
+// Scalable returns whether f is made of only full blocks and spaces, in
+// which case text rendered in f can be scaled up with ScaleText.
+func (f *Font) Scalable() bool {
+	return f.scalable
+}
+
+// widestDigit returns the digit with the widest glyph in f.
+func (f *Font) widestDigit() rune {
+	widest, width := '0', -1
+	for r := '0'; r <= '9'; r++ {
+		if g, ok := f.glyphs[r]; ok && len(g[0]) > width {
+			widest, width = r, len(g[0])
+		}
+	}
+	return widest
+}
+
+// Tabular returns f with the glyphs of every digit padded, on both
+// sides, to the width of the widest digit. Text rendered in it keeps
+// it's width as it's digits change.
+func (f *Font) Tabular() *Font {
+	if f.tabular != nil {
+		return f.tabular
+	}
+	t := &Font{
+		Name:     f.Name,
+		Height:   f.Height,
+		glyphs:   make(map[rune][][]rune, len(f.glyphs)),
+		scalable: f.scalable,
+		scaled:   f.scaled,
+	}
+	for r, g := range f.glyphs {
+		t.glyphs[r] = g
+	}
+	width := 0
+	if g, ok := f.glyphs[f.widestDigit()]; ok {
+		width = len(g[0])
+	}
+	for r := '0'; r <= '9'; r++ {
+		g, ok := f.glyphs[r]
+		if !ok {
+			continue
+		}
+		pad := width - len(g[0])
+		padded := make([][]rune, len(g))
+		for i, row := range g {
+			padded[i] = []rune(strings.Repeat(" ", pad/2) + string(row) + strings.Repeat(" ", pad-pad/2))
+		}
+		t.glyphs[r] = padded
+	}
+	t.tabular = t
+	f.tabular = t
+	return t
+}
+
+// ScaleText returns text with every cell repeated n times horizontally
+// and vertically.
+func ScaleText(text []string, n int) []string {
+	scaled := make([]string, 0, len(text)*n)
+	for _, s := range text {
+		var line strings.Builder
+		for _, r := range s {
+			line.WriteString(strings.Repeat(string(r), n))
+		}
+		for i := 0; i < n; i++ {
+			scaled = append(scaled, line.String())
+		}
+	}
+	return scaled
+}
+
+// textWidth returns the width of the widest line of text.
+func textWidth(text []string) int {
+	w := 0
+	for _, s := range text {
+		w = max(w, runewidth.StringWidth(s))
+	}
+	return w
+}
@@ -0,0 +1,89 @@
+package widget
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFont(t *testing.T) {
+	// tagged is a font of 2 rows that defines every required character
+	// as #, and then a code tagged character.
+	var tagged strings.Builder
+	tagged.WriteString("flf2a$ 2 1 4 0 0\n")
+	for range requiredCharacters {
+		tagged.WriteString("#@\n#@@\n")
+	}
+	tagged.WriteString("0x2713 CHECK MARK\n$v@\nv$@@\n")
+
+	tests := []struct {
+		name    string
+		src     string
+		want    map[rune][]string
+		wantErr bool
+	}{
+		{
+			name: "hardblanks and endmarks",
+			src:  "flf2a$ 2 1 4 0 1\na comment\n$$@\n$$@@\n|$|@\n|@@\n",
+			want: map[rune][]string{
+				' ': {"  ", "  "},
+				'!': {"| |", "|  "},
+			},
+		},
+		{
+			name: "code tagged",
+			src:  tagged.String(),
+			want: map[rune][]string{
+				'~': {"#", "#"},
+				'✓': {" v", "v "},
+			},
+		},
+		{name: "empty", src: "", wantErr: true},
+		{name: "not figlet", src: "tlf2a$ 2 1 4 0 0\n", wantErr: true},
+		{name: "no hardblank", src: "flf2a 2 1 4 0 0\n", wantErr: true},
+		{name: "short header", src: "flf2a$ 2 1 4 0\n", wantErr: true},
+		{name: "bad height", src: "flf2a$ two 1 4 0 0\n", wantErr: true},
+		{name: "zero height", src: "flf2a$ 0 1 4 0 0\n", wantErr: true},
+		{name: "bad comments", src: "flf2a$ 2 1 4 0 -1\n", wantErr: true},
+		{name: "missing comments", src: "flf2a$ 2 1 4 0 3\none\n", wantErr: true},
+		{name: "cut off character", src: "flf2a$ 2 1 4 0 0\n$@\n", wantErr: true},
+		{name: "bad code tag", src: tagged.String() + "tick\n#@\n#@@\n", wantErr: true},
+		{name: "missing tagged character", src: tagged.String() + "0x2714\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		f, err := ParseFont(strings.NewReader(tt.src))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: ParseFont() error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		for r, want := range tt.want {
+			g, ok := f.Glyph(r)
+			if !ok {
+				t.Errorf("%s: %q not defined", tt.name, r)
+				continue
+			}
+			got := make([]string, len(g))
+			for i, row := range g {
+				got[i] = string(row)
+			}
+			if strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("%s: glyph of %q = %q, want %q", tt.name, r, got, want)
+			}
+		}
+	}
+}
+
+func TestEmbeddedFonts(t *testing.T) {
+	for _, name := range FontNames() {
+		f, err := LoadFont(name)
+		if err != nil {
+			t.Errorf("LoadFont(%q) error = %v", name, err)
+			continue
+		}
+		if !f.Defines("0123456789:") {
+			t.Errorf("font %q doesn't define every digit", name)
+		}
+	}
+}
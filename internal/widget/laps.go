@@ -2,61 +2,366 @@ package widget
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"time"
 
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+// What the delta column of a LapTable compares each lap to.
+const (
+	// DeltaPrevious compares each lap to the lap before it.
+	DeltaPrevious = iota
+
+	// DeltaMean compares each lap to the mean of the laps.
+	DeltaMean
+)
+
+// Lap is a lap of a LapTable.
+type Lap struct {
+	// Number is the number of the lap, starting with 1.
+	Number int
+
+	// Seconds is the lap time, and Total the time at the end of the
+	// lap, in seconds.
+	Seconds, Total int
+
+	// At is the wall clock time the lap was taken at.
+	At time.Time
+
+	// Note is the note attached to the lap, if any.
+	Note string
+
+	// Rest is the rest taken after the lap, in seconds, when the laps
+	// are sets of an exercise. The lap time includes the rest taken
+	// before it.
+	Rest int
+
+	// restWithin is the rest taken within the lap, after the sets that
+	// were deleted into it.
+	restWithin int
+}
+
 type LapTable struct {
 	*Table
 
 	// Format will be used to format the lap and total time.
 	Format func(seconds int) string
+
+	// laps are the laps of the table, oldest first. The rows of the
+	// table show them newest first.
+	laps []Lap
+
+	// history are the laps before each of the operations on them, most
+	// recent last, to undo them.
+	history [][]Lap
+
+	// deltaMode is what the delta column compares each lap to, either
+	// DeltaPrevious or DeltaMean.
+	deltaMode int
+
+	// showTime shows the column of the wall clock time of the laps.
+	showTime bool
+
+	// showSets shows the columns of the set time and the rest taken of
+	// the laps.
+	showSets bool
+
+	// distance is the distance of a lap, in metres. The pace and speed
+	// columns are shown when it is set.
+	distance float64
+
+	// Styles of the fastest and the slowest lap.
+	bestStyle, worstStyle tcell.Style
 }
 
+// lapTimeLayout is the layout of the wall clock time of the laps.
+const lapTimeLayout = "15:04:05"
+
+// lapSetColumn is the column of the set time of the laps, the first of
+// the columns that may be shown after the total time.
+const lapSetColumn = 3
+
 // NewLapTable returns a new LapTable. The seconds are formatted using
 // SecondWithColons by default.
 func NewLapTable() *LapTable {
-	t := NewTable("Lap", "Lap time", "Total")
-	return &LapTable{
-		Table:  t,
-		Format: SecondWithColons,
+	t := NewTable("Lap", "Lap time", "Total", "Delta", "Note")
+	l := &LapTable{
+		Table:      t,
+		Format:     SecondWithColons,
+		deltaMode:  DeltaPrevious,
+		bestStyle:  tcell.StyleDefault,
+		worstStyle: tcell.StyleDefault,
 	}
+	t.SetFooterFunc(l.footer)
+	return l
+}
+
+// SetFormat sets format as the format of the lap and total time, and
+// formats the previously added laps with it.
+func (l *LapTable) SetFormat(format func(seconds int) string) *LapTable {
+	l.Format = format
+	l.refresh()
+	return l
+}
+
+// DeltaMode returns what the delta column compares each lap to.
+func (l *LapTable) DeltaMode() int {
+	return l.deltaMode
+}
+
+// SetDeltaMode sets what the delta column compares each lap to. Must be
+// one of DeltaPrevious or DeltaMean.
+func (l *LapTable) SetDeltaMode(mode int) *LapTable {
+	l.deltaMode = mode
+	l.refresh()
+	return l
+}
+
+// ShowTime returns whether the column of the wall clock time of the laps
+// is shown.
+func (l *LapTable) ShowTime() bool {
+	return l.showTime
+}
+
+// SetShowTime sets whether the column of the wall clock time of the laps
+// is shown, after the total time.
+func (l *LapTable) SetShowTime(show bool) *LapTable {
+	if show == l.showTime {
+		return l
+	}
+	l.showTime = show
+	if show {
+		l.InsertColumn(l.timeColumn(), "At")
+	} else {
+		l.RemoveColumn(l.timeColumn())
+	}
+	l.refresh()
+	return l
+}
+
+// paceColumn returns the column of the pace of the laps, which follows
+// the set and rest columns.
+func (l *LapTable) paceColumn() int {
+	if l.showSets {
+		return lapSetColumn + 2
+	}
+	return lapSetColumn
+}
+
+// timeColumn returns the column of the wall clock time of the laps,
+// which follows the pace and speed columns.
+func (l *LapTable) timeColumn() int {
+	if l.distance > 0 {
+		return l.paceColumn() + 2
+	}
+	return l.paceColumn()
+}
+
+// ShowSets returns whether the columns of the set time and the rest
+// taken of the laps are shown.
+func (l *LapTable) ShowSets() bool {
+	return l.showSets
+}
+
+// SetShowSets sets whether the laps are shown as sets of an exercise,
+// with the columns of the set time, the lap time without the rest taken
+// before it, and the rest taken after it.
+func (l *LapTable) SetShowSets(show bool) *LapTable {
+	if show == l.showSets {
+		return l
+	}
+	l.showSets = show
+	if show {
+		l.InsertColumn(lapSetColumn, "Rest")
+		l.InsertColumn(lapSetColumn, "Set")
+	} else {
+		l.RemoveColumn(lapSetColumn)
+		l.RemoveColumn(lapSetColumn)
+	}
+	l.refresh()
+	return l
+}
+
+// SetRest sets the rest taken after the lap at row row to seconds. Row
+// indexing starts with the row after the header rows.
+func (l *LapTable) SetRest(row, seconds int) *LapTable {
+	i := len(l.laps) - 1 - row
+	if i < 0 || i >= len(l.laps) {
+		return l
+	}
+	l.save()
+	l.laps = append([]Lap(nil), l.laps...)
+	l.laps[i].Rest = seconds
+	l.refresh()
+	return l
+}
+
+// Row returns the row of the lap taken at at, or -1 if there is none.
+// Unlike it's number, the time a lap was taken at doesn't change as the
+// laps before it are deleted. Row indexing starts with the row after the
+// header rows.
+func (l *LapTable) Row(at time.Time) int {
+	for i, lap := range l.laps {
+		if lap.At.Equal(at) {
+			return len(l.laps) - 1 - i
+		}
+	}
+	return -1
+}
+
+// setSeconds returns the set time of lap i, that is, it's lap time
+// without the rest taken before it, or within it.
+func (l *LapTable) setSeconds(i int) int {
+	seconds := l.laps[i].Seconds - l.laps[i].restWithin
+	if i > 0 {
+		seconds -= l.laps[i-1].Rest
+	}
+	return seconds
+}
+
+// Distance returns the distance of a lap, in metres, or 0 if it isn't
+// set.
+func (l *LapTable) Distance() float64 {
+	return l.distance
+}
+
+// SetDistance sets the distance of a lap to metres. A distance greater
+// than 0 shows the pace, in minutes per kilometre, and the speed, in
+// kilometres per hour, of every lap, after the total time.
+func (l *LapTable) SetDistance(metres float64) *LapTable {
+	if (metres > 0) != (l.distance > 0) {
+		if metres > 0 {
+			l.InsertColumn(l.paceColumn(), "Speed")
+			l.InsertColumn(l.paceColumn(), "Pace")
+		} else {
+			l.RemoveColumn(l.paceColumn())
+			l.RemoveColumn(l.paceColumn())
+		}
+	}
+	l.distance = metres
+	l.refresh()
+	return l
+}
+
+// Pace returns the pace of a lap of seconds, in seconds per kilometre.
+func (l *LapTable) Pace(seconds int) float64 {
+	return float64(seconds) / (l.distance / 1000)
+}
+
+// Speed returns the speed of a lap of seconds, in kilometres per hour.
+func (l *LapTable) Speed(seconds int) float64 {
+	if seconds == 0 {
+		return 0
+	}
+	return l.distance / float64(seconds) * 3.6
+}
+
+// SetBestStyle sets the style of the fastest lap.
+func (l *LapTable) SetBestStyle(s tcell.Style) *LapTable {
+	l.bestStyle = s
+	l.refresh()
+	return l
+}
+
+// SetWorstStyle sets the style of the slowest lap.
+func (l *LapTable) SetWorstStyle(s tcell.Style) *LapTable {
+	l.worstStyle = s
+	l.refresh()
+	return l
+}
+
+// SetCellStyle sets s as the default style for all previously added
+// cells and any newly added cells. The fastest and the slowest lap keep
+// their own styles.
+func (l *LapTable) SetCellStyle(s tcell.Style) *LapTable {
+	l.Table.SetCellStyle(s)
+	l.refresh()
+	return l
+}
+
+// save saves the laps to history before an operation on them.
+func (l *LapTable) save() {
+	l.history = append(l.history, append([]Lap(nil), l.laps...))
+}
+
+// Undo undoes the last operation on the laps, adding, deleting or
+// noting one, and returns whether there was one.
+func (l *LapTable) Undo() bool {
+	if len(l.history) == 0 {
+		return false
+	}
+	l.laps = l.history[len(l.history)-1]
+	l.history = l.history[:len(l.history)-1]
+	l.refresh()
+	return true
 }
 
 // AddLap adds a new lap into l with Lap time total time as
 // totalSeconds.
 func (l *LapTable) AddLap(totalSeconds int) *LapTable {
-	var newCell = func(text string, ref interface{}) *tview.TableCell {
-		c := tview.NewTableCell(text)
-		c.SetReference(ref)
-		c.SetAlign(tview.AlignCenter)
-		c.SetStyle(l.Table.GetCellStyle())
-		return c
+	lap := Lap{Number: 1, Seconds: totalSeconds, Total: totalSeconds, At: time.Now()}
+	if n := len(l.laps); n > 0 {
+		lap.Number = l.laps[n-1].Number + 1
+		lap.Seconds = totalSeconds - l.laps[n-1].Total
 	}
+	l.save()
+	l.laps = append(l.laps, lap)
+	l.refresh()
+	return l
+}
 
-	var lap, lapSeconds int
-
-	if l.GetRowCount() == 0 {
-		lap, lapSeconds = 1, totalSeconds
-	} else {
-		i, _, total := l.GetLap(0)
-		lap, lapSeconds = i+1, totalSeconds - total
+// DeleteLap deletes the lap at row row. The time of the lap is added to
+// the lap after it, so that the totals stay the same, and the laps after
+// it are numbered again. The rest taken after the lap is kept as rest
+// within the lap after it, rather than counted in it's set time. Row
+// indexing starts with the row after the header rows.
+func (l *LapTable) DeleteLap(row int) *LapTable {
+	i := len(l.laps) - 1 - row
+	if i < 0 || i >= len(l.laps) {
+		return l
+	}
+	l.save()
+	laps := append([]Lap(nil), l.laps[:i]...)
+	for j, lap := range l.laps[i+1:] {
+		lap.Number--
+		if j == 0 {
+			lap.Seconds += l.laps[i].Seconds
+			lap.restWithin += l.laps[i].Rest + l.laps[i].restWithin
+		}
+		laps = append(laps, lap)
 	}
+	l.laps = laps
+	l.refresh()
+	return l
+}
 
-	l.InsertRow(2)
-	l.SetCell(0, 0, newCell(fmt.Sprint(lap), lap))
-	l.SetCell(0, 1, newCell(l.Format(lapSeconds), lapSeconds))
-	l.SetCell(0, 2, newCell(l.Format(totalSeconds), totalSeconds))
+// SetNote attaches note to the lap at row row. Row indexing starts with
+// the row after the header rows.
+func (l *LapTable) SetNote(row int, note string) *LapTable {
+	i := len(l.laps) - 1 - row
+	if i < 0 || i >= len(l.laps) {
+		return l
+	}
+	l.save()
+	l.laps = append([]Lap(nil), l.laps...)
+	l.laps[i].Note = note
+	l.refresh()
 	return l
 }
 
+// Laps returns the laps of l, oldest first.
+func (l *LapTable) Laps() []Lap {
+	return append([]Lap(nil), l.laps...)
+}
+
 // GetLap returns the lap at row row. Row indexing starts with the row
 // after the header rows.
 func (l *LapTable) GetLap(row int) (lap, lapSeconds, totalSeconds int) {
-	var getData = func(col int) int {
-		return l.GetCell(row, col).GetReference().(int)
-	}
-	return getData(0), getData(1), getData(2)
+	data := l.laps[len(l.laps)-1-row]
+	return data.Number, data.Seconds, data.Total
 }
 
 // GetHighlightedLap returns the currently highlighted lap.
@@ -64,3 +369,151 @@ func (l *LapTable) GetHighlightedLap() (lap, lapSeconds, totalSeconds int) {
 	row, _ := l.GetSelection()
 	return l.GetLap(row - 2)
 }
+
+// HighlightedRow returns the row of the currently highlighted lap, or -1
+// if there are no laps. Row indexing starts with the row after the
+// header rows.
+func (l *LapTable) HighlightedRow() int {
+	if len(l.laps) == 0 {
+		return -1
+	}
+	row, _ := l.GetSelection()
+	return min(max(row-2, 0), len(l.laps)-1)
+}
+
+// lapSeconds returns the lap times of l, oldest first.
+func (l *LapTable) lapSeconds() []int {
+	laps := make([]int, len(l.laps))
+	for i, lap := range l.laps {
+		laps[i] = lap.Seconds
+	}
+	return laps
+}
+
+// LapStats returns the mean, the median and the standard deviation of
+// the lap times, in seconds.
+func (l *LapTable) LapStats() (mean, median, stddev float64) {
+	laps := l.lapSeconds()
+	if len(laps) == 0 {
+		return 0, 0, 0
+	}
+	for _, s := range laps {
+		mean += float64(s)
+	}
+	mean /= float64(len(laps))
+
+	sorted := append([]int(nil), laps...)
+	sort.Ints(sorted)
+	if n := len(sorted); n%2 == 1 {
+		median = float64(sorted[n/2])
+	} else {
+		median = float64(sorted[n/2-1]+sorted[n/2]) / 2
+	}
+
+	for _, s := range laps {
+		stddev += (float64(s) - mean) * (float64(s) - mean)
+	}
+	stddev = math.Sqrt(stddev / float64(len(laps)))
+	return mean, median, stddev
+}
+
+// formatDelta returns delta seconds formatted with it's sign.
+func (l *LapTable) formatDelta(delta int) string {
+	switch {
+	case delta > 0:
+		return "+" + l.Format(delta)
+	case delta < 0:
+		return "-" + l.Format(-delta)
+	}
+	return "±" + l.Format(0)
+}
+
+// refresh updates the rows of the table to the laps of l, with the
+// deltas and the styles of the fastest and the slowest lap.
+func (l *LapTable) refresh() {
+	var newCell = func(text string) *tview.TableCell {
+		c := tview.NewTableCell(text)
+		c.SetAlign(tview.AlignCenter)
+		return c
+	}
+
+	// The rows missing are added by SetCell below.
+	n := len(l.laps)
+	for l.GetRowCount() > n {
+		l.RemoveRow(2)
+	}
+	if n == 0 {
+		return
+	}
+
+	laps := l.lapSeconds()
+	mean, _, _ := l.LapStats()
+
+	best, worst := 0, 0
+	for i, s := range laps {
+		if s < laps[best] {
+			best = i
+		}
+		if s > laps[worst] {
+			worst = i
+		}
+	}
+
+	for i, lap := range l.laps {
+		row := n - 1 - i
+		delta := ""
+		if l.deltaMode == DeltaMean {
+			delta = l.formatDelta(lap.Seconds - int(math.Round(mean)))
+		} else if i > 0 {
+			delta = l.formatDelta(lap.Seconds - laps[i-1])
+		}
+		cells := []string{fmt.Sprint(lap.Number), l.Format(lap.Seconds), l.Format(lap.Total)}
+		if l.showSets {
+			rest := ""
+			if lap.Rest > 0 {
+				rest = l.Format(lap.Rest)
+			}
+			cells = append(cells, l.Format(l.setSeconds(i)), rest)
+		}
+		if l.distance > 0 {
+			pace := int(math.Round(l.Pace(lap.Seconds)))
+			cells = append(cells,
+				fmt.Sprintf("%d:%02d /km", pace/60, pace%60),
+				fmt.Sprintf("%.1f km/h", l.Speed(lap.Seconds)))
+		}
+		if l.showTime {
+			cells = append(cells, lap.At.Format(lapTimeLayout))
+		}
+		cells = append(cells, delta, lap.Note)
+		for c, text := range cells {
+			l.SetCell(row, c, newCell(text))
+		}
+
+		style := l.GetCellStyle()
+		// A single lap, or laps all as fast, are neither.
+		if laps[best] != laps[worst] {
+			if i == best {
+				style = l.bestStyle
+			} else if i == worst {
+				style = l.worstStyle
+			}
+		}
+		for c := 0; c < l.GetColumnCount(); c++ {
+			l.GetCell(row, c).SetStyle(style)
+		}
+	}
+}
+
+// footer returns the lap statistics drawn below the table, once there
+// are laps.
+func (l *LapTable) footer() string {
+	if len(l.laps) == 0 {
+		return ""
+	}
+	mean, median, stddev := l.LapStats()
+	var format = func(s float64) string {
+		return l.Format(int(math.Round(s)))
+	}
+	return fmt.Sprintf("mean %s  median %s  σ %s",
+		format(mean), format(median), format(stddev))
+}
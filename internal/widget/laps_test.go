@@ -0,0 +1,53 @@
+package widget
+
+import "testing"
+
+func TestLapTableDeleteSet(t *testing.T) {
+	l := NewLapTable()
+	l.SetShowSets(true)
+	// A 20s set, a 5s rest, and a 15s set.
+	l.AddLap(20)
+	l.SetRest(0, 5)
+	l.AddLap(40)
+
+	l.DeleteLap(1)
+	laps := l.Laps()
+	if len(laps) != 1 {
+		t.Fatalf("len(Laps()) = %d, want 1", len(laps))
+	}
+	if got := l.setSeconds(0); got != 35 {
+		t.Errorf("set time = %d, want 35", got)
+	}
+	if laps[0].Seconds != 40 || laps[0].Total != 40 {
+		t.Errorf("lap = %+v, want lap time and total of 40", laps[0])
+	}
+
+	// Undo takes back the delete, the second set, and then the rest.
+	l.Undo()
+	if got := l.Laps()[0].Rest; got != 5 {
+		t.Errorf("rest after undoing the delete = %d, want 5", got)
+	}
+	l.Undo()
+	l.Undo()
+	if got := l.Laps()[0].Rest; got != 0 {
+		t.Errorf("rest after undoing it = %d, want 0", got)
+	}
+}
+
+func TestLapTableRow(t *testing.T) {
+	l := NewLapTable()
+	l.AddLap(10)
+	l.AddLap(20)
+	l.AddLap(30)
+	laps := l.Laps()
+	last := laps[2].At
+	l.DeleteLap(2)
+	if got := l.Row(last); got != 0 {
+		t.Errorf("Row of the last lap after deleting the first = %d, want 0", got)
+	}
+	l.Undo()
+	l.Undo()
+	if got := l.Row(last); got != -1 {
+		t.Errorf("Row of an undone lap = %d, want -1", got)
+	}
+}
@@ -2,17 +2,44 @@ package widget
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
 	"github.com/rivo/tview"
 )
 
+// Styles the progress bar can be drawn in.
+const (
+	// ProgressBox draws the bar three cells thick, in full blocks, with a
+	// box-drawing shadow.
+	ProgressBox = iota
+
+	// ProgressThin draws the bar a single heavy line thin.
+	ProgressThin
+
+	// ProgressBraille draws the bar a single row of Braille patterns
+	// thin.
+	ProgressBraille
+
+	// ProgressASCII draws the bar with ASCII characters only, like so
+	// [#####-----].
+	ProgressASCII
+)
+
+// Eighth blocks, of which the progress bar fills the cell it ends in,
+// indexed by the eighths of the cell that are filled.
+var (
+	leftEighths  = []rune{' ', '▏', '▎', '▍', '▌', '▋', '▊', '▉', '█'}
+	lowerEighths = []rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+)
+
 type ProgressBar struct {
 	*tview.Box
 
-	// percent tracks the progress percentage, and belongs to the closed
-	// interval [0, 100].
-	percent int
+	// progress tracks the filled fraction of the progress bar, and
+	// belongs to the closed interval [0, 1].
+	progress float64
 
 	// align determines the vertical alignment of the progress bar.
 	//
@@ -20,6 +47,25 @@ type ProgressBar struct {
 	// all of the available width.
 	align int
 
+	// horizontalAlign determines the horizontal alignment of a vertical
+	// progress bar, which fills all of the available height instead.
+	horizontalAlign int
+
+	// style is the style the progress bar is drawn in, one of
+	// ProgressBox, ProgressThin, ProgressBraille or ProgressASCII.
+	style int
+
+	// vertical makes the progress bar fill from the bottom up.
+	vertical bool
+
+	// markers are the fractions of the progress bar at which markers,
+	// like the boundaries between the items of a queue, are drawn.
+	markers []float64
+
+	// Label returns the label drawn over the middle of the progress
+	// bar, like it's percent. No label is drawn if it is nil.
+	Label func() string
+
 	// TextColor is the color for the fill characters of the progress bar.
 	TextColor tcell.Color
 
@@ -32,11 +78,13 @@ type ProgressBar struct {
 // and center aligned.
 func NewProgressBar() *ProgressBar {
 	return &ProgressBar{
-		Box:         tview.NewBox(),
-		percent:     0,
-		align:       AlignCenter,
-		TextColor:   tcell.ColorWhite,
-		ShadowColor: tcell.ColorGrey,
+		Box:             tview.NewBox(),
+		progress:        0,
+		align:           AlignCenter,
+		horizontalAlign: tview.AlignCenter,
+		style:           ProgressBox,
+		TextColor:       tcell.ColorWhite,
+		ShadowColor:     tcell.ColorGrey,
 	}
 }
 
@@ -47,9 +95,45 @@ func (p *ProgressBar) SetAlign(align int) *ProgressBar {
 	return p
 }
 
-// Percent returns the progress percent.
+// SetHorizontalAlign sets the horizontal alignment of a vertical
+// progress bar. Must be one of tview.AlignCenter, tview.AlignLeft or
+// tview.AlignRight.
+func (p *ProgressBar) SetHorizontalAlign(align int) *ProgressBar {
+	p.horizontalAlign = align
+	return p
+}
+
+// SetStyle sets the style of the progress bar. Must be one of
+// ProgressBox, ProgressThin, ProgressBraille or ProgressASCII.
+func (p *ProgressBar) SetStyle(style int) *ProgressBar {
+	p.style = style
+	return p
+}
+
+// SetVertical sets whether the progress bar is vertical, filling from
+// the bottom up, or horizontal, filling from the left.
+func (p *ProgressBar) SetVertical(vertical bool) *ProgressBar {
+	p.vertical = vertical
+	return p
+}
+
+// SetMarkers sets the fractions of the progress bar, each belonging to
+// the closed interval [0, 1], at which markers are drawn.
+func (p *ProgressBar) SetMarkers(markers ...float64) *ProgressBar {
+	p.markers = markers
+	return p
+}
+
+// Percent returns the progress percent, rounded down.
 func (p *ProgressBar) Percent() int {
-	return p.percent
+	// Leave room for the error of the float multiplication, like in
+	// 0.29*100.
+	return int(math.Floor(p.progress*100 + 1e-9))
+}
+
+// Progress returns the filled fraction of the progress bar.
+func (p *ProgressBar) Progress() float64 {
+	return p.progress
 }
 
 // SetPercent sets the progress to v percent. v must belong to the
@@ -60,69 +144,312 @@ func (p *ProgressBar) SetPercent(v int) (*ProgressBar, error) {
 	} else if v > 100 {
 		return p, fmt.Errorf("progress: progress percent %d larger than 100", v)
 	}
-	p.percent = v
+	p.progress = float64(v) / 100
 	return p, nil
 }
 
+// SetProgress sets the filled fraction of the progress bar to v, which
+// is finer than a percent. v must belong to the closed interval [0, 1],
+// returns p and error otherwise.
+func (p *ProgressBar) SetProgress(v float64) (*ProgressBar, error) {
+	if v < 0 {
+		return p, fmt.Errorf("progress: negative progress %g", v)
+	} else if v > 1 {
+		return p, fmt.Errorf("progress: progress %g larger than 1", v)
+	}
+	p.progress = v
+	return p, nil
+}
+
+// thickness returns the number of cells the progress bar takes across
+// it's length.
+func (p *ProgressBar) thickness() int {
+	if p.style == ProgressBox {
+		return 3
+	}
+	return 1
+}
+
+// eighths returns the number of eighths of a cell filled of a bar n
+// cells long.
+func (p *ProgressBar) eighths(n int) int {
+	return int(math.Round(p.progress * float64(8*n)))
+}
+
 func (p *ProgressBar) Draw(screen tcell.Screen) {
 	p.Box.DrawForSubclass(screen, p)
 
-	// minHeight of the progress bar
-	// ██
-	// ██
-	// ╚═
-	const minHeight = 3
-
 	x, y, width, height := p.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	background := p.GetBackgroundColor()
+	shadowStyle := tcell.StyleDefault.Foreground(p.ShadowColor).Background(background)
+	fillStyle := tcell.StyleDefault.Foreground(p.TextColor).Background(background)
+
+	if p.vertical {
+		thickness := min(p.thickness(), width)
+		if p.horizontalAlign == tview.AlignCenter {
+			x += getCenter(width, thickness)
+		} else if p.horizontalAlign == tview.AlignRight {
+			x += width - thickness
+		}
+		p.drawVertical(screen, x, y, height, fillStyle, shadowStyle)
+		return
+	}
+
+	thickness := min(p.thickness(), height)
 	if p.align == AlignCenter {
-		y += getCenter(height, minHeight)
+		y += getCenter(height, thickness)
 	} else if p.align == AlignDown {
-		y += height - minHeight
+		y += height - thickness
 	}
+	p.drawHorizontal(screen, x, y, width, fillStyle, shadowStyle)
+}
+
+// drawHorizontal draws the progress bar, width cells long, from x, y.
+func (p *ProgressBar) drawHorizontal(screen tcell.Screen, x, y, width int, fillStyle, shadowStyle tcell.Style) {
+	// The part of the bar the label and the markers are drawn over, and
+	// the character of the markers.
+	labelX, labelY, labelWidth := x, y, width
+	markerY, marker := y, '┼'
+	filled := p.eighths(width)
 
-	const (
-		fillChar             = '█'
-		shadowHoriChar       = '═'
-		shadowVertiChar      = '║'
-		shadowUpperLeftChar  = '╔'
-		shadowLowerLeftChar  = '╚'
-		shadowUpperRightChar = '╗'
-		shadowLowerRightChar = '╝'
-	)
+	switch p.style {
+	case ProgressThin:
+		// A heavy line has only a half cell precision.
+		halves := (filled + 2) / 4
+		for i := 0; i < width; i++ {
+			switch {
+			case 2*i+2 <= halves:
+				screen.SetContent(x+i, y, '━', nil, fillStyle)
+			case 2*i+1 == halves:
+				screen.SetContent(x+i, y, '╸', nil, fillStyle)
+			default:
+				screen.SetContent(x+i, y, '─', nil, shadowStyle)
+			}
+		}
 
-	shadowStyle := tcell.StyleDefault.Foreground(p.ShadowColor).Background(p.GetBackgroundColor())
-	fillStyle := tcell.StyleDefault.Foreground(p.TextColor).Background(p.GetBackgroundColor())
+	case ProgressBraille:
+		cv := newCanvas(2*width, 4)
+		dots := (filled + 2) / 4
+		for dx := 0; dx < cv.width; dx++ {
+			if dx < dots {
+				for dy := 0; dy < 4; dy++ {
+					cv.set(dx, dy, dotText)
+				}
+			} else {
+				cv.set(dx, 3, dotShadow)
+			}
+		}
+		cv.drawBraille(screen, x, y, fillStyle, shadowStyle)
+		marker = '⡇'
 
-	xProgressEnd := width * p.percent / 100
-	xEnd := x + width - 1
+	case ProgressASCII:
+		if width < 3 {
+			return
+		}
+		screen.SetContent(x, y, '[', nil, shadowStyle)
+		screen.SetContent(x+width-1, y, ']', nil, shadowStyle)
+		cells := int(math.Round(p.progress * float64(width-2)))
+		for i := 0; i < width-2; i++ {
+			if i < cells {
+				screen.SetContent(x+1+i, y, '#', nil, fillStyle)
+			} else {
+				screen.SetContent(x+1+i, y, '-', nil, shadowStyle)
+			}
+		}
+		labelX, labelWidth = x+1, width-2
+		marker = '|'
 
-	// line 1
-	for i := 0; i < xProgressEnd; i++ {
-		screen.SetCell(x+i, y, fillStyle, fillChar)
+	default:
+		// ██▌═╗
+		// ██▌ ║
+		// ╚═══╝
+		const (
+			shadowHoriChar       = '═'
+			shadowVertiChar      = '║'
+			shadowUpperLeftChar  = '╔'
+			shadowLowerLeftChar  = '╚'
+			shadowUpperRightChar = '╗'
+			shadowLowerRightChar = '╝'
+		)
+		xEnd := x + width - 1
+		for row := 0; row < 2; row++ {
+			for i := 0; i < width; i++ {
+				if n := filled - 8*i; n > 0 {
+					screen.SetContent(x+i, y+row, leftEighths[min(n, 8)], nil, fillStyle)
+				} else if row == 0 {
+					screen.SetContent(x+i, y+row, shadowHoriChar, nil, shadowStyle)
+				}
+			}
+		}
+		screen.SetContent(xEnd, y, shadowUpperRightChar, nil, shadowStyle)
+		screen.SetContent(xEnd, y+1, shadowVertiChar, nil, shadowStyle)
+		if filled == 0 {
+			screen.SetContent(x, y, shadowUpperLeftChar, nil, shadowStyle)
+			screen.SetContent(x, y+1, shadowVertiChar, nil, shadowStyle)
+		}
+		screen.SetContent(x, y+2, shadowLowerLeftChar, nil, shadowStyle)
+		for i := 1; i < width; i++ {
+			screen.SetContent(x+i, y+2, shadowHoriChar, nil, shadowStyle)
+		}
+		screen.SetContent(xEnd, y+2, shadowLowerRightChar, nil, shadowStyle)
+		labelY = y + 1
+		markerY, marker = y+2, '╩'
 	}
-	for i := xProgressEnd; i < width; i++ {
-		screen.SetCell(x+i, y, shadowStyle, shadowHoriChar)
+
+	for _, i := range p.markerCells(labelWidth) {
+		screen.SetContent(labelX+i, markerY, marker, nil, shadowStyle)
 	}
-	screen.SetCell(xEnd, y, shadowStyle, shadowUpperRightChar)
-	if xProgressEnd == 0 {
-		screen.SetCell(x, y, shadowStyle, shadowUpperLeftChar)
+	p.drawLabel(screen, labelX, labelY, labelWidth, false, fillStyle)
+}
+
+// drawVertical draws the progress bar, height cells long, from x, y.
+func (p *ProgressBar) drawVertical(screen tcell.Screen, x, y, height int, fillStyle, shadowStyle tcell.Style) {
+	// The part of the bar the label and the markers are drawn over, and
+	// the character of the markers.
+	labelX, labelY, labelHeight := x, y, height
+	markerX, marker := x, '┼'
+	yEnd := y + height - 1
+
+	switch p.style {
+	case ProgressThin:
+		halves := (p.eighths(height) + 2) / 4
+		for i := 0; i < height; i++ {
+			switch {
+			case 2*i+2 <= halves:
+				screen.SetContent(x, yEnd-i, '┃', nil, fillStyle)
+			case 2*i+1 == halves:
+				screen.SetContent(x, yEnd-i, '╻', nil, fillStyle)
+			default:
+				screen.SetContent(x, yEnd-i, '│', nil, shadowStyle)
+			}
+		}
+
+	case ProgressBraille:
+		cv := newCanvas(2, 4*height)
+		dots := int(math.Round(p.progress * float64(cv.height)))
+		for dy := 0; dy < cv.height; dy++ {
+			if dy >= cv.height-dots {
+				cv.set(0, dy, dotText)
+				cv.set(1, dy, dotText)
+			} else {
+				cv.set(0, dy, dotShadow)
+			}
+		}
+		cv.drawBraille(screen, x, y, fillStyle, shadowStyle)
+		marker = '⠛'
+
+	case ProgressASCII:
+		if height < 3 {
+			return
+		}
+		screen.SetContent(x, y, '-', nil, shadowStyle)
+		screen.SetContent(x, yEnd, '-', nil, shadowStyle)
+		cells := int(math.Round(p.progress * float64(height-2)))
+		for i := 0; i < height-2; i++ {
+			if i < cells {
+				screen.SetContent(x, yEnd-1-i, '#', nil, fillStyle)
+			} else {
+				screen.SetContent(x, yEnd-1-i, '|', nil, shadowStyle)
+			}
+		}
+		labelY, labelHeight = y+1, height-2
+		marker = '+'
+
+	default:
+		// ╔═╗
+		// ║ ║
+		// ██║
+		// ╚═╝
+		if height < 3 {
+			return
+		}
+		screen.SetContent(x, y, '╔', nil, shadowStyle)
+		screen.SetContent(x+1, y, '═', nil, shadowStyle)
+		screen.SetContent(x+2, y, '╗', nil, shadowStyle)
+		filled := p.eighths(height - 2)
+		for i := 0; i < height-2; i++ {
+			row := yEnd - 1 - i
+			if n := filled - 8*i; n > 0 {
+				screen.SetContent(x, row, lowerEighths[min(n, 8)], nil, fillStyle)
+				screen.SetContent(x+1, row, lowerEighths[min(n, 8)], nil, fillStyle)
+			} else {
+				screen.SetContent(x, row, '║', nil, shadowStyle)
+			}
+			screen.SetContent(x+2, row, '║', nil, shadowStyle)
+		}
+		screen.SetContent(x, yEnd, '╚', nil, shadowStyle)
+		screen.SetContent(x+1, yEnd, '═', nil, shadowStyle)
+		screen.SetContent(x+2, yEnd, '╝', nil, shadowStyle)
+		labelX, labelY, labelHeight = x+1, y+1, height-2
+		markerX, marker = x+2, '╣'
 	}
 
-	// line 2
-	y++
-	for i := 0; i < xProgressEnd; i++ {
-		screen.SetCell(x+i, y, fillStyle, fillChar)
+	// The bar fills from the bottom up, and so are the markers placed.
+	for _, i := range p.markerCells(labelHeight) {
+		screen.SetContent(markerX, labelY+labelHeight-1-i, marker, nil, shadowStyle)
 	}
-	screen.SetCell(xEnd, y, shadowStyle, shadowVertiChar)
-	if xProgressEnd == 0 {
-		screen.SetCell(x, y, shadowStyle, shadowVertiChar)
+	p.drawLabel(screen, labelX, labelY, labelHeight, true, fillStyle)
+}
+
+// markerCells returns the cells, of a bar n cells long, that the markers
+// fall on. Markers at either end of the bar are left out.
+func (p *ProgressBar) markerCells(n int) []int {
+	var cells []int
+	for _, m := range p.markers {
+		i := int(math.Round(m * float64(n)))
+		if i <= 0 || i >= n {
+			continue
+		}
+		cells = append(cells, i)
 	}
+	return cells
+}
 
-	// line 3
-	y++
-	screen.SetCell(x, y, shadowStyle, shadowLowerLeftChar)
-	for i := 1; i < width; i++ {
-		screen.SetCell(x+i, y, shadowStyle, shadowHoriChar)
+// drawLabel draws the label centered over the part of the progress bar
+// n cells long from x, y; across it, with a space on either side, or
+// down it if vertical, a character per row. The label is drawn in the
+// reverse of fillStyle over the filled cells, so that it stays readable.
+// A label longer than n cells isn't drawn.
+func (p *ProgressBar) drawLabel(screen tcell.Screen, x, y, n int, vertical bool, fillStyle tcell.Style) {
+	if p.Label == nil {
+		return
+	}
+	label := p.Label()
+	if label == "" {
+		return
+	}
+	length := len([]rune(label))
+	if !vertical {
+		// Keep a space on either side of the label, so that it doesn't
+		// run into the bar.
+		label = " " + label + " "
+		length = runewidth.StringWidth(label)
+	}
+	if length > n {
+		return
+	}
+	start := getCenter(n, length)
+	reverse := fillStyle.Foreground(p.GetBackgroundColor()).Background(p.TextColor)
+	filled := p.progress * float64(n)
+	i := start
+	for _, r := range label {
+		// Whether the middle of the cell is filled.
+		style := fillStyle
+		if vertical {
+			if float64(n-i)-0.5 < filled {
+				style = reverse
+			}
+			screen.SetContent(x, y+i, r, nil, style)
+			i++
+			continue
+		}
+		if float64(i)+0.5 < filled {
+			style = reverse
+		}
+		screen.SetContent(x+i, y, r, nil, style)
+		i += runewidth.RuneWidth(r)
 	}
-	screen.SetCell(xEnd, y, shadowStyle, shadowLowerRightChar)
 }
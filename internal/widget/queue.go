@@ -2,10 +2,30 @@ package widget
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+// Statuses of the items of a Queue.
+const (
+	ItemPending = iota
+	ItemRunning
+	ItemPaused
+	ItemDone
+	ItemSkipped
+)
+
+// statusIcons are drawn before the number of an item with the status.
+// Pending items show only their number.
+var statusIcons = map[int]string{
+	ItemRunning: queueHeadIcon,
+	ItemPaused:  "❚❚",
+	ItemDone:    "✓",
+	ItemSkipped: "↷",
+}
+
 type Queue struct {
 	*Table
 
@@ -13,6 +33,25 @@ type Queue struct {
 	// header rows).
 	head int
 
+	// status is the status of each item, one of ItemPending,
+	// ItemRunning, ItemPaused, ItemDone or ItemSkipped.
+	status []int
+
+	// ended is the time each done item ended at.
+	ended []time.Time
+
+	// hold is whether each item waits, paused, to be started once the
+	// queue advances to it.
+	hold []bool
+
+	// titled is whether the column of the titles of the items is shown.
+	titled bool
+
+	// An optional function which gets called, in place of selecting
+	// the row, whenever the user selects a done item, like to ask
+	// whether to restart it.
+	doneSelected func(row int)
+
 	// An optional function which gets called whenever the user selects
 	// a cell (eg: presses Enter on a cell). row is the row of the
 	// selected cell. Row indexing starts with the row after the header
@@ -22,12 +61,18 @@ type Queue struct {
 
 const queueHeadIcon = "->"
 
+// queueHoldIcon is drawn after the number of an item that is held.
+const queueHoldIcon = "⏎"
+
 // NewQueue returns a new Queue, with the duration column formatted
-// using SecondWithColons.
+// using SecondWithColons. The first item is running, the rest pending.
 func NewQueue(durations ...int) *Queue {
 	q := &Queue{
-		Table:  NewTable("Queue", "Timer duration"),
+		Table:  NewTable("Queue", "Timer duration", "Ends at"),
 		head:   -1,
+		status: make([]int, len(durations)),
+		ended:  make([]time.Time, len(durations)),
+		hold:   make([]bool, len(durations)),
 	}
 
 	var newCell = func(text string, ref interface{}) *tview.TableCell {
@@ -41,14 +86,21 @@ func NewQueue(durations ...int) *Queue {
 	for i, duration := range durations {
 		q.SetCell(i, 0, newCell(fmt.Sprint(i+1), i+1))
 		q.SetCell(i, 1, newCell(SecondWithColons(duration), duration))
+		q.SetCell(i, 2, newCell("", nil))
 	}
 	q.head = 0
-	q.GetCell(0, 0).SetText(queueHeadIcon)
+	q.status[0] = ItemRunning
+	q.refresh(0)
 
 	// Pressing the Enter key leads to "selecting" that row.
 	q.Table.SetSelectedFunc(func(row, column int) {
 		// get row index after removing the header rows
-		q.Select(row-2)
+		row -= 2
+		if q.status[row] == ItemDone && q.doneSelected != nil {
+			q.doneSelected(row)
+			return
+		}
+		q.Select(row)
 	})
 
 	return q
@@ -57,12 +109,56 @@ func NewQueue(durations ...int) *Queue {
 // SetDurationFormat formats the duration column's text using format.
 func (q *Queue) SetDurationFormat(format func(seconds int) string) *Queue {
 	for r := 0; r < q.GetRowCount(); r++ {
-		cell := q.GetCell(r, 1)
+		cell := q.GetCell(r, q.durationColumn())
 		cell.SetText(format(cell.GetReference().(int)))
 	}
 	return q
 }
 
+// durationColumn returns the column of the durations of the items, which
+// follows the titles, if shown.
+func (q *Queue) durationColumn() int {
+	if q.titled {
+		return 2
+	}
+	return 1
+}
+
+// SetTitles shows titles as the titles of the items, in order, in a
+// column after their numbers.
+func (q *Queue) SetTitles(titles ...string) *Queue {
+	if !q.titled {
+		q.titled = true
+		q.InsertColumn(1, "Item")
+	}
+	for r := 0; r < q.GetRowCount(); r++ {
+		c := tview.NewTableCell("")
+		if r < len(titles) {
+			c.SetText(titles[r])
+		}
+		c.SetAlign(tview.AlignCenter)
+		q.SetCell(r, 1, c)
+		q.refresh(r)
+	}
+	return q
+}
+
+// Head returns the row of the current item of the queue. Row indexing
+// starts with the row after the header rows.
+func (q *Queue) Head() int {
+	return q.head
+}
+
+// Durations returns the durations, in seconds, of the items of the
+// queue in order.
+func (q *Queue) Durations() []int {
+	durations := make([]int, q.GetRowCount())
+	for r := range durations {
+		durations[r] = q.GetCell(r, q.durationColumn()).GetReference().(int)
+	}
+	return durations
+}
+
 // SetSelectedFunc sets an optional function which gets called whenever
 // the user selects a cell (eg: presses Enter on a cell). row is the row
 // of the selected cell. Row indexing starts with the row after the
@@ -72,16 +168,116 @@ func (q *Queue) SetSelectedFunc(handler func(row int)) *Queue {
 	return q
 }
 
-// Select selects row row. This also fires "selected" handler, if set.
+// SetDoneSelectedFunc sets an optional function which gets called, in
+// place of selecting the row, whenever the user selects a done item.
 // Row indexing starts with the row after the header rows.
-func (q *Queue) Select(row int) *Queue {
-	// Remove queueHeadIcon from current row.
-	cell := q.GetCell(q.head, 0)
-	cell.SetText(fmt.Sprint(cell.GetReference()))
+func (q *Queue) SetDoneSelectedFunc(handler func(row int)) *Queue {
+	q.doneSelected = handler
+	return q
+}
+
+// Status returns the status of the item at row. Row indexing starts
+// with the row after the header rows.
+func (q *Queue) Status(row int) int {
+	return q.status[row]
+}
+
+// SetStatus sets the status of the item at row. Row indexing starts
+// with the row after the header rows.
+func (q *Queue) SetStatus(row, status int) *Queue {
+	q.status[row] = status
+	q.refresh(row)
+	return q
+}
 
-	// Attach queueHeadIcon to the given row.
+// Hold returns whether the item at row is held, that is, waits paused
+// to be started once the queue advances to it. Row indexing starts with
+// the row after the header rows.
+func (q *Queue) Hold(row int) bool {
+	return q.hold[row]
+}
+
+// SetHold sets whether the item at row is held. Row indexing starts
+// with the row after the header rows.
+func (q *Queue) SetHold(row int, hold bool) *Queue {
+	q.hold[row] = hold
+	q.refresh(row)
+	return q
+}
+
+// Done marks the current item as done, now.
+func (q *Queue) Done() *Queue {
+	q.ended[q.head] = time.Now()
+	return q.SetStatus(q.head, ItemDone)
+}
+
+// SetEndTimes sets the end times of the current item, which ends after
+// remaining, and of the pending items after it, which are taken to
+// follow it one after the other. Done items keep the time they ended at.
+func (q *Queue) SetEndTimes(remaining time.Duration) *Queue {
+	at := time.Now().Add(remaining)
+	for r := 0; r < q.GetRowCount(); r++ {
+		text := ""
+		switch {
+		case q.status[r] == ItemDone:
+			text = q.ended[r].Format("15:04")
+		case r == q.head:
+			text = at.Format("15:04")
+		case r > q.head && q.status[r] == ItemPending:
+			at = at.Add(time.Duration(q.GetCell(r, q.durationColumn()).GetReference().(int)) * time.Second)
+			text = at.Format("15:04")
+		}
+		q.GetCell(r, q.durationColumn()+1).SetText(text)
+	}
+	return q
+}
+
+// SetCellStyle sets s as the default style for all previously added
+// cells and any newly added cells. Done items are drawn dimmed.
+func (q *Queue) SetCellStyle(s tcell.Style) *Queue {
+	q.Table.SetCellStyle(s)
+	for r := range q.status {
+		q.refresh(r)
+	}
+	return q
+}
+
+// refresh updates the icon and the style of the item at row to it's
+// status.
+func (q *Queue) refresh(row int) {
+	cell := q.GetCell(row, 0)
+	text := fmt.Sprint(cell.GetReference())
+	if icon, ok := statusIcons[q.status[row]]; ok {
+		text = icon + " " + text
+	}
+	if q.hold[row] {
+		text += " " + queueHoldIcon
+	}
+	cell.SetText(text)
+
+	style := q.GetCellStyle()
+	if q.status[row] == ItemDone {
+		style = style.Dim(true)
+	}
+	for c := 0; c < q.GetColumnCount(); c++ {
+		q.GetCell(row, c).SetStyle(style)
+	}
+}
+
+// Select selects row row, which then is running. The item it leaves,
+// unless done, is skipped if row is after it, or pending otherwise. This
+// also fires "selected" handler, if set. Row indexing starts with the
+// row after the header rows.
+func (q *Queue) Select(row int) *Queue {
+	if s := q.status[q.head]; s == ItemRunning || s == ItemPaused {
+		if row > q.head {
+			q.SetStatus(q.head, ItemSkipped)
+		} else {
+			q.SetStatus(q.head, ItemPending)
+		}
+	}
 	q.head = row
-	q.GetCell(q.head, 0).SetText(queueHeadIcon)
+	q.SetStatus(q.head, ItemRunning)
 
 	if q.selected != nil {
 		q.selected(row)
@@ -0,0 +1,28 @@
+package widget
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueueSetEndTimes(t *testing.T) {
+	q := NewQueue(60, 60, 60, 60)
+	// The second item is done, and the third skipped, before going back
+	// to the first.
+	q.Select(1)
+	q.Done()
+	q.Select(2)
+	q.Select(3)
+	q.Select(0)
+	q.SetEndTimes(time.Hour)
+
+	if got := q.GetCell(1, 2).Text; got != q.ended[1].Format("15:04") {
+		t.Errorf("end time of the done item = %q, want %q", got, q.ended[1].Format("15:04"))
+	}
+	if got := q.GetCell(2, 2).Text; got != "" {
+		t.Errorf("end time of the skipped item = %q, want none", got)
+	}
+	if got := q.GetCell(3, 2).Text; got == "" {
+		t.Errorf("end time of the pending item is missing")
+	}
+}
@@ -0,0 +1,202 @@
+package widget
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// How a ResultTable orders it's results.
+const (
+	// SortByTime orders the results by finish time, fastest first.
+	SortByTime = iota
+
+	// SortByBib orders the results by bib number.
+	SortByBib
+)
+
+// Result is the finish of a competitor in a ResultTable.
+type Result struct {
+	// Bib is the bib number of the competitor, and Name their name, if
+	// known.
+	Bib, Name string
+
+	// Time is the finish time, on the race clock, and At the wall clock
+	// time of the finish.
+	Time time.Duration
+	At   time.Time
+
+	// Place is the place of the finish, starting with 1, or 0 if the
+	// finish is flagged, as a duplicate or an unknown bib. Flagged
+	// finishes don't push the finishes after them down a place.
+	Place int
+
+	// Duplicate flags a bib that had already finished, and Unknown a bib
+	// that isn't one of the entries.
+	Duplicate, Unknown bool
+}
+
+// ResultTable is a table of the finishes of the competitors of a race.
+type ResultTable struct {
+	*Table
+
+	// Format will be used to format the finish times.
+	Format func(d time.Duration) string
+
+	// entries are the names of the competitors by bib. Any bib is known
+	// if there are none.
+	entries map[string]string
+
+	// results are the finishes, in the order they were recorded.
+	results []Result
+
+	// sortBy is the order of the rows, either SortByTime or SortByBib.
+	sortBy int
+
+	// flagStyle is the style of a flagged finish.
+	flagStyle tcell.Style
+}
+
+// NewResultTable returns a new ResultTable of the competitors in entries,
+// the names of the competitors by bib. The times are formatted using
+// DurationWithColons by default.
+func NewResultTable(entries map[string]string) *ResultTable {
+	return &ResultTable{
+		Table:     NewTable("Place", "Bib", "Name", "Time", "Flag"),
+		Format:    DurationWithColons,
+		entries:   entries,
+		sortBy:    SortByTime,
+		flagStyle: tcell.StyleDefault,
+	}
+}
+
+// SetFlagStyle sets the style of a flagged finish, a duplicate or an
+// unknown bib.
+func (r *ResultTable) SetFlagStyle(style tcell.Style) *ResultTable {
+	r.flagStyle = style
+	r.refresh()
+	return r
+}
+
+// SetCellStyle sets style as the default style for all previously added
+// cells and any newly added cells. The flagged finishes keep their own
+// style.
+func (r *ResultTable) SetCellStyle(style tcell.Style) *ResultTable {
+	r.Table.SetCellStyle(style)
+	r.refresh()
+	return r
+}
+
+// SortBy returns how the results are ordered.
+func (r *ResultTable) SortBy() int {
+	return r.sortBy
+}
+
+// SetSortBy sets how the results are ordered. Must be one of SortByTime
+// or SortByBib.
+func (r *ResultTable) SetSortBy(sortBy int) *ResultTable {
+	r.sortBy = sortBy
+	r.refresh()
+	return r
+}
+
+// Finish records the finish of the competitor with bib at time t on the
+// race clock, and returns the result.
+func (r *ResultTable) Finish(bib string, t time.Duration) Result {
+	res := Result{Bib: bib, Time: t, At: time.Now()}
+	if name, ok := r.entries[bib]; ok {
+		res.Name = name
+	} else if len(r.entries) > 0 {
+		res.Unknown = true
+	}
+	place := 1
+	for _, prev := range r.results {
+		if prev.Bib == bib {
+			res.Duplicate = true
+		}
+		if prev.Place != 0 {
+			place++
+		}
+	}
+	if !res.Duplicate && !res.Unknown {
+		res.Place = place
+	}
+	r.results = append(r.results, res)
+	r.refresh()
+	return res
+}
+
+// Undo takes back the last finish recorded, and returns whether there
+// was one.
+func (r *ResultTable) Undo() bool {
+	if len(r.results) == 0 {
+		return false
+	}
+	r.results = r.results[:len(r.results)-1]
+	r.refresh()
+	return true
+}
+
+// Results returns the results, in the order of the rows.
+func (r *ResultTable) Results() []Result {
+	results := append([]Result(nil), r.results...)
+	if r.sortBy == SortByBib {
+		sort.SliceStable(results, func(i, j int) bool {
+			return bibLess(results[i].Bib, results[j].Bib)
+		})
+	} else {
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Time < results[j].Time
+		})
+	}
+	return results
+}
+
+// bibLess reports whether bib a comes before bib b. Numbers are ordered
+// by their value, and before any other bib.
+func bibLess(a, b string) bool {
+	na, erra := strconv.Atoi(a)
+	nb, errb := strconv.Atoi(b)
+	switch {
+	case erra == nil && errb == nil:
+		return na < nb
+	case erra == nil || errb == nil:
+		return erra == nil
+	}
+	return a < b
+}
+
+// refresh updates the rows of the table to the results.
+func (r *ResultTable) refresh() {
+	var newCell = func(text string, style tcell.Style) *tview.TableCell {
+		c := tview.NewTableCell(text)
+		c.SetAlign(tview.AlignCenter)
+		c.SetStyle(style)
+		return c
+	}
+
+	for r.GetRowCount() > len(r.results) {
+		r.RemoveRow(2)
+	}
+	for i, res := range r.Results() {
+		place, flag, style := "", "", r.GetCellStyle()
+		if res.Place != 0 {
+			place = fmt.Sprint(res.Place)
+		}
+		switch {
+		case res.Duplicate:
+			flag, style = "duplicate", r.flagStyle
+		case res.Unknown:
+			flag, style = "unknown", r.flagStyle
+		}
+		r.SetCell(i, 0, newCell(place, style))
+		r.SetCell(i, 1, newCell(res.Bib, style))
+		r.SetCell(i, 2, newCell(res.Name, style))
+		r.SetCell(i, 3, newCell(r.Format(res.Time), style))
+		r.SetCell(i, 4, newCell(flag, style))
+	}
+}
@@ -0,0 +1,26 @@
+package widget
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResultTableFinishPlaces(t *testing.T) {
+	r := NewResultTable(map[string]string{"1": "ann", "2": "bob", "3": "cat"})
+	finishes := []struct {
+		bib   string
+		place int
+	}{
+		{"2", 1},
+		{"99", 0}, // unknown
+		{"2", 0},  // duplicate
+		{"1", 2},
+		{"3", 3},
+	}
+	for i, f := range finishes {
+		res := r.Finish(f.bib, time.Duration(i+1)*time.Minute)
+		if res.Place != f.place {
+			t.Errorf("Finish(%q) place = %d, want %d", f.bib, res.Place, f.place)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package widget
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Ring is a progress indicator drawn as a ring of Braille patterns, that
+// fills clockwise from twelve o'clock. Another primitive, like the
+// Clock, can be drawn in the hole of the ring.
+type Ring struct {
+	*tview.Box
+
+	// progress tracks the filled fraction of the ring, and belongs to
+	// the closed interval [0, 1].
+	progress float64
+
+	// center is the primitive drawn in the hole of the ring, if any.
+	center tview.Primitive
+
+	// TextColor is the color of the filled part of the ring.
+	TextColor tcell.Color
+
+	// ShadowColor is the color of the rest of the ring.
+	ShadowColor tcell.Color
+}
+
+// NewRing returns a new Ring initialised at 0% progress, with nothing
+// in it's center.
+func NewRing() *Ring {
+	return &Ring{
+		Box:         tview.NewBox(),
+		TextColor:   tcell.ColorWhite,
+		ShadowColor: tcell.ColorGrey,
+	}
+}
+
+// SetCenter sets the primitive drawn in the hole of the ring, which is
+// resized to the largest box that fits in it.
+func (r *Ring) SetCenter(p tview.Primitive) *Ring {
+	r.center = p
+	return r
+}
+
+// Progress returns the filled fraction of the ring.
+func (r *Ring) Progress() float64 {
+	return r.progress
+}
+
+// SetProgress sets the filled fraction of the ring to v. v must belong
+// to the closed interval [0, 1], returns r and error otherwise.
+func (r *Ring) SetProgress(v float64) (*Ring, error) {
+	if v < 0 {
+		return r, fmt.Errorf("ring: negative progress %g", v)
+	} else if v > 1 {
+		return r, fmt.Errorf("ring: progress %g larger than 1", v)
+	}
+	r.progress = v
+	return r, nil
+}
+
+func (r *Ring) Draw(screen tcell.Screen) {
+	r.DrawForSubclass(screen, r)
+
+	x, y, width, height := r.GetInnerRect()
+
+	cv, c := newDial(width, height)
+	if cv == nil {
+		if r.center != nil {
+			r.center.SetRect(x, y, width, height)
+			r.center.Draw(screen)
+		}
+		return
+	}
+	outer := float64(c)
+	thickness := math.Max(2, outer/8)
+	inner := outer - thickness
+	cv.fillDial(inner, outer, r.progress, dotText, dotShadow)
+
+	w, h := cv.brailleSize()
+	x += getCenter(width, w)
+	y += getCenter(height, h)
+
+	background := r.GetBackgroundColor()
+	shadowStyle := tcell.StyleDefault.Foreground(r.ShadowColor).Background(background)
+	textStyle := tcell.StyleDefault.Foreground(r.TextColor).Background(background)
+	cv.drawBraille(screen, x, y, textStyle, shadowStyle)
+
+	if r.center == nil {
+		return
+	}
+	// The box in the hole is wider than it is tall, like the text of a
+	// clock; it's corners touch the hole at about 35 degrees above and
+	// below three and nine o'clock. Leave a dot of room for the ring.
+	hole := inner - 1
+	halfWidth := int(hole * 0.82 / 2)
+	halfHeight := int(hole * 0.57 / 4)
+	cx, cy := x+c/2, y+c/4
+	r.center.SetRect(cx-halfWidth, cy-halfHeight, 2*halfWidth+1, 2*halfHeight+1)
+	r.center.Draw(screen)
+}
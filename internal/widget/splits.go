@@ -0,0 +1,237 @@
+package widget
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Segment is a named segment of a run, like a level of a game.
+type Segment struct {
+	Name string
+
+	// PB is the split time, that is, the time since the start of the
+	// run, at the end of the segment in the personal best run. It is 0
+	// if there is no personal best.
+	PB time.Duration
+
+	// Best is the best time of the segment itself in any run. It is 0
+	// if the segment hasn't been run.
+	Best time.Duration
+}
+
+// SplitTable is a table of the segments of a run and their splits,
+// compared to the personal best.
+type SplitTable struct {
+	*Table
+
+	// Format will be used to format the split times and deltas.
+	Format func(d time.Duration) string
+
+	// segments are the segments of the run, in order.
+	segments []Segment
+
+	// splits are the split times of the segments completed so far.
+	splits []time.Duration
+
+	// Styles of a split ahead of, and behind, the personal best, and of a
+	// segment faster than it's best ever.
+	aheadStyle, behindStyle, goldStyle tcell.Style
+}
+
+const splitsHeadIcon = "->"
+
+// NewSplitTable returns a new SplitTable of segments. The times are
+// formatted using DurationWithColons by default.
+func NewSplitTable(segments []Segment) *SplitTable {
+	s := &SplitTable{
+		Table:       NewTable("Segment", "Delta", "Time"),
+		Format:      DurationWithColons,
+		segments:    segments,
+		aheadStyle:  tcell.StyleDefault,
+		behindStyle: tcell.StyleDefault,
+		goldStyle:   tcell.StyleDefault,
+	}
+	s.SetFooterFunc(s.footer)
+	s.refresh()
+	return s
+}
+
+// SetFormat sets the format of the split times and deltas.
+func (s *SplitTable) SetFormat(format func(d time.Duration) string) *SplitTable {
+	s.Format = format
+	s.refresh()
+	return s
+}
+
+// SetAheadStyle sets the style of a delta ahead of the personal best.
+func (s *SplitTable) SetAheadStyle(style tcell.Style) *SplitTable {
+	s.aheadStyle = style
+	s.refresh()
+	return s
+}
+
+// SetBehindStyle sets the style of a delta behind the personal best.
+func (s *SplitTable) SetBehindStyle(style tcell.Style) *SplitTable {
+	s.behindStyle = style
+	s.refresh()
+	return s
+}
+
+// SetGoldStyle sets the style of the delta of a segment faster than it's
+// best ever.
+func (s *SplitTable) SetGoldStyle(style tcell.Style) *SplitTable {
+	s.goldStyle = style
+	s.refresh()
+	return s
+}
+
+// SetCellStyle sets style as the default style for all previously added
+// cells and any newly added cells. The deltas keep their own styles.
+func (s *SplitTable) SetCellStyle(style tcell.Style) *SplitTable {
+	s.Table.SetCellStyle(style)
+	s.refresh()
+	return s
+}
+
+// Segments returns the segments of the run.
+func (s *SplitTable) Segments() []Segment {
+	return append([]Segment(nil), s.segments...)
+}
+
+// Splits returns the split times of the segments completed so far.
+func (s *SplitTable) Splits() []time.Duration {
+	return append([]time.Duration(nil), s.splits...)
+}
+
+// Done returns whether every segment is completed.
+func (s *SplitTable) Done() bool {
+	return len(s.splits) == len(s.segments)
+}
+
+// Split completes the current segment at split time t.
+func (s *SplitTable) Split(t time.Duration) *SplitTable {
+	if s.Done() {
+		return s
+	}
+	s.splits = append(s.splits, t)
+	s.refresh()
+	return s
+}
+
+// Undo takes back the last split.
+func (s *SplitTable) Undo() *SplitTable {
+	if len(s.splits) > 0 {
+		s.splits = s.splits[:len(s.splits)-1]
+		s.refresh()
+	}
+	return s
+}
+
+// Reset takes back every split, to start the run again.
+func (s *SplitTable) Reset() *SplitTable {
+	s.splits = nil
+	s.refresh()
+	return s
+}
+
+// SetSegments replaces the segments of the run, like with their new
+// personal best and best segment times.
+func (s *SplitTable) SetSegments(segments []Segment) *SplitTable {
+	s.segments = segments
+	s.refresh()
+	return s
+}
+
+// SegmentTime returns the time of the completed segment i itself.
+func (s *SplitTable) SegmentTime(i int) time.Duration {
+	if i == 0 {
+		return s.splits[0]
+	}
+	return s.splits[i] - s.splits[i-1]
+}
+
+// SumOfBest returns the sum of the best times of the segments, the
+// fastest run possible so far, and whether every segment has a best
+// time. A segment of the current run faster than it's best counts.
+func (s *SplitTable) SumOfBest() (time.Duration, bool) {
+	var sum time.Duration
+	complete := true
+	for i, seg := range s.segments {
+		best := seg.Best
+		if i < len(s.splits) && (best == 0 || s.SegmentTime(i) < best) {
+			best = s.SegmentTime(i)
+		}
+		if best == 0 {
+			complete = false
+		}
+		sum += best
+	}
+	return sum, complete
+}
+
+// formatDelta returns d formatted with it's sign.
+func (s *SplitTable) formatDelta(d time.Duration) string {
+	if d < 0 {
+		return "-" + s.Format(-d)
+	}
+	return "+" + s.Format(d)
+}
+
+// refresh updates the rows of the table to the segments and splits.
+func (s *SplitTable) refresh() {
+	var newCell = func(text string, style tcell.Style) *tview.TableCell {
+		c := tview.NewTableCell(text)
+		c.SetAlign(tview.AlignCenter)
+		c.SetStyle(style)
+		return c
+	}
+
+	for i, seg := range s.segments {
+		name, delta, split := seg.Name, "", ""
+		style, deltaStyle := s.GetCellStyle(), s.GetCellStyle()
+		switch {
+		case i < len(s.splits):
+			split = s.Format(s.splits[i])
+			if seg.PB != 0 {
+				d := s.splits[i] - seg.PB
+				delta = s.formatDelta(d)
+				deltaStyle = s.behindStyle
+				if d < 0 {
+					deltaStyle = s.aheadStyle
+				}
+			}
+			if seg.Best != 0 && s.SegmentTime(i) < seg.Best {
+				deltaStyle = s.goldStyle
+			}
+		case i == len(s.splits):
+			name = splitsHeadIcon + " " + name
+			fallthrough
+		default:
+			// The segments to come show the personal best dimmed.
+			if seg.PB != 0 {
+				split = s.Format(seg.PB)
+			}
+			style = style.Dim(true)
+		}
+		s.SetCell(i, 0, newCell(name, style))
+		s.SetCell(i, 1, newCell(delta, deltaStyle))
+		s.SetCell(i, 2, newCell(split, style))
+	}
+}
+
+// footer returns the sum of best drawn below the table.
+func (s *SplitTable) footer() string {
+	text := "sum of best "
+	if sum, ok := s.SumOfBest(); ok {
+		text += s.Format(sum)
+	} else {
+		text += "-"
+	}
+	if n := len(s.segments); n > 0 && s.segments[n-1].PB != 0 {
+		text += fmt.Sprintf("  pb %s", s.Format(s.segments[n-1].PB))
+	}
+	return text
+}
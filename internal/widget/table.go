@@ -12,31 +12,101 @@ type Table struct {
 
 	// The default style for data cells.
 	cellStyle tcell.Style
+
+	// The styles of the header and underline cells.
+	headerStyle, underlineStyle tcell.Style
+
+	// An optional function which returns the text of the footer, drawn
+	// on the last row below the table, and it's style.
+	footer      func() string
+	footerStyle tcell.Style
+}
+
+// newHeaderCell returns a header cell with text and style.
+func newHeaderCell(text string, style tcell.Style) *tview.TableCell {
+	c := tview.NewTableCell(text)
+	c.SetStyle(style)
+	c.SetAlign(tview.AlignCenter)
+	c.SetExpansion(1)
+	c.NotSelectable = true
+	return c
 }
 
 // NewTable returns a new Table.
 func NewTable(headers ...string) *Table {
-	var newCell = func(text string, style tcell.Style) *tview.TableCell {
-		c := tview.NewTableCell(text)
-		c.SetStyle(style)
-		c.SetAlign(tview.AlignCenter)
-		c.SetExpansion(1)
-		c.NotSelectable = true
-		return c
-	}
-
 	t := tview.NewTable()
 	defStyle := tcell.StyleDefault
 	for i, header := range headers {
-		t.SetCell(0, i, newCell(header, defStyle))
+		t.SetCell(0, i, newHeaderCell(header, defStyle))
 	}
 	for i, header := range headers {
-		t.SetCell(1, i, newCell( strings.Repeat("▔", len(header)), defStyle))
+		t.SetCell(1, i, newHeaderCell(strings.Repeat("▔", len(header)), defStyle))
 	}
 
 	t.SetSelectable(true, false)
 	t.SetFixed(2, 0)
-	return &Table{t, defStyle}
+	return &Table{
+		Table:          t,
+		cellStyle:      defStyle,
+		headerStyle:    defStyle,
+		underlineStyle: defStyle,
+		footerStyle:    defStyle,
+	}
+}
+
+// InsertColumn inserts a column with header before the column col. The
+// cells in col and to it's right are shifted to the right by one column.
+func (t *Table) InsertColumn(col int, header string) *Table {
+	rows := t.rows()
+	for r := range rows {
+		cell := tview.NewTableCell("")
+		switch r {
+		case 0:
+			cell = newHeaderCell(header, t.headerStyle)
+		case 1:
+			cell = newHeaderCell(strings.Repeat("▔", len(header)), t.underlineStyle)
+		}
+		rows[r] = append(rows[r][:col], append([]*tview.TableCell{cell}, rows[r][col:]...)...)
+	}
+	t.setRows(rows)
+	return t
+}
+
+// RemoveColumn removes the column col, along with it's header. The cells
+// to it's right are shifted to the left by one column.
+func (t *Table) RemoveColumn(col int) *Table {
+	rows := t.rows()
+	for r := range rows {
+		rows[r] = append(rows[r][:col], rows[r][col+1:]...)
+	}
+	t.setRows(rows)
+	return t
+}
+
+// rows returns every cell of t, header rows included, row by row.
+func (t *Table) rows() [][]*tview.TableCell {
+	rows := make([][]*tview.TableCell, t.Table.GetRowCount())
+	for r := range rows {
+		rows[r] = make([]*tview.TableCell, t.Table.GetColumnCount())
+		for c := range rows[r] {
+			rows[r][c] = t.Table.GetCell(r, c)
+		}
+	}
+	return rows
+}
+
+// setRows replaces the cells of t with rows, header rows included.
+//
+// tview.Table's own InsertColumn and RemoveColumn don't update it's
+// column count, so the table is cleared, which resets it, and set
+// again instead.
+func (t *Table) setRows(rows [][]*tview.TableCell) {
+	t.Table.Clear()
+	for r, row := range rows {
+		for c, cell := range row {
+			t.Table.SetCell(r, c, cell)
+		}
+	}
 }
 
 // GetCell returns the cell at the given position. The position
@@ -73,6 +143,7 @@ func (t *Table) SetCell(row, col int, cell *tview.TableCell) *Table {
 
 // SetHeaderStyle sets the style of all header cells as s.
 func (t *Table) SetHeaderStyle(s tcell.Style) *Table {
+	t.headerStyle = s
 	for i := 0; i < t.GetColumnCount(); i++ {
 		t.Table.GetCell(0, i).SetStyle(s)
 	}
@@ -81,12 +152,52 @@ func (t *Table) SetHeaderStyle(s tcell.Style) *Table {
 
 // SetUnderlineStyle sets the style for all of the underline cells as s.
 func (t *Table) SetUnderlineStyle(s tcell.Style) *Table {
+	t.underlineStyle = s
 	for i := 0; i < t.GetColumnCount(); i++ {
 		t.Table.GetCell(1, i).SetStyle(s)
 	}
 	return t
 }
 
+// SetFooterFunc sets an optional function which returns the text of the
+// footer, drawn on the last row below the table. No footer is drawn
+// while it returns "".
+func (t *Table) SetFooterFunc(footer func() string) *Table {
+	t.footer = footer
+	return t
+}
+
+// SetFooterStyle sets the style of the footer.
+func (t *Table) SetFooterStyle(s tcell.Style) *Table {
+	t.footerStyle = s
+	return t
+}
+
+// Draw draws the table, and the footer, if any, on the last row below
+// it.
+func (t *Table) Draw(screen tcell.Screen) {
+	text := ""
+	if t.footer != nil {
+		text = t.footer()
+	}
+	x, y, width, height := t.GetRect()
+	if text == "" || height < 2 {
+		t.Table.Draw(screen)
+		return
+	}
+	t.SetRect(x, y, width, height-1)
+	t.Table.Draw(screen)
+	t.SetRect(x, y, width, height)
+
+	y += height - 1
+	style := t.footerStyle.Background(t.GetBackgroundColor())
+	for i := 0; i < width; i++ {
+		screen.SetContent(x+i, y, ' ', nil, style)
+	}
+	fg, _, _ := style.Decompose()
+	tview.Print(screen, text, x, y, width, tview.AlignCenter, fg)
+}
+
 // GetCellStyle returns Table's cell style.
 func (t *Table) GetCellStyle() tcell.Style {
 	return t.cellStyle
@@ -0,0 +1,43 @@
+package widget
+
+import (
+	"reflect"
+	"testing"
+)
+
+// headers returns the texts of the header row of t.
+func headers(t *Table) []string {
+	var h []string
+	for c := 0; c < t.GetColumnCount(); c++ {
+		h = append(h, t.Table.GetCell(0, c).Text)
+	}
+	return h
+}
+
+func TestTableInsertRemoveColumn(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(l *LapTable)
+		want  []string
+	}{
+		{"none", func(l *LapTable) {}, []string{"Lap", "Lap time", "Total", "Delta", "Note"}},
+		{"time", func(l *LapTable) {
+			l.SetShowTime(true)
+		}, []string{"Lap", "Lap time", "Total", "At", "Delta", "Note"}},
+		{"time toggled off", func(l *LapTable) {
+			l.SetShowTime(true)
+			l.SetShowTime(false)
+		}, []string{"Lap", "Lap time", "Total", "Delta", "Note"}},
+		{"sets and distance", func(l *LapTable) {
+			l.SetShowSets(true)
+			l.SetDistance(400)
+		}, []string{"Lap", "Lap time", "Total", "Set", "Rest", "Pace", "Speed", "Delta", "Note"}},
+	}
+	for _, tt := range tests {
+		l := NewLapTable()
+		tt.setup(l)
+		if got := headers(l.Table); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: headers = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
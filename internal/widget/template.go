@@ -0,0 +1,137 @@
+package widget
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Template formats a duration following a template such as %H:%M:%S.
+//
+// The verbs are,
+//
+//	%D, %d  days
+//	%H, %h  hours
+//	%M, %m  minutes
+//	%S, %s  seconds
+//	%f      tenths of a second
+//	%%      a literal %
+//
+// Upper case verbs are zero padded to two digits, lower case verbs are
+// not padded. The largest unit in the template holds the whole of the
+// duration, so %M:%S formats 1 hour and 2 minutes as 62:00.
+type Template struct {
+	// source is the template as it was parsed.
+	source string
+
+	// parts are the literal text and verbs of the template, in order.
+	parts []templatePart
+
+	// largest is the largest unit in the template.
+	largest time.Duration
+}
+
+// templatePart is either a verb, or literal text when verb is zero.
+type templatePart struct {
+	verb    byte
+	literal string
+}
+
+// templateUnits maps the verbs to their unit.
+var templateUnits = map[byte]time.Duration{
+	'D': 24 * time.Hour, 'd': 24 * time.Hour,
+	'H': time.Hour, 'h': time.Hour,
+	'M': time.Minute, 'm': time.Minute,
+	'S': time.Second, 's': time.Second,
+	'f': time.Second / 10,
+}
+
+// ParseTemplate parses the template src.
+func ParseTemplate(src string) (*Template, error) {
+	t := &Template{source: src}
+	var literal strings.Builder
+	for i := 0; i < len(src); i++ {
+		if src[i] != '%' {
+			literal.WriteByte(src[i])
+			continue
+		}
+		i++
+		if i == len(src) {
+			return nil, fmt.Errorf("template: %q ends with a lone %%", src)
+		}
+		if src[i] == '%' {
+			literal.WriteByte('%')
+			continue
+		}
+		unit, ok := templateUnits[src[i]]
+		if !ok {
+			return nil, fmt.Errorf("template: unknown verb %%%c in %q", src[i], src)
+		}
+		if literal.Len() > 0 {
+			t.parts = append(t.parts, templatePart{literal: literal.String()})
+			literal.Reset()
+		}
+		t.parts = append(t.parts, templatePart{verb: src[i]})
+		if unit > t.largest {
+			t.largest = unit
+		}
+	}
+	if literal.Len() > 0 {
+		t.parts = append(t.parts, templatePart{literal: literal.String()})
+	}
+	return t, nil
+}
+
+// String returns the template as it was parsed.
+func (t *Template) String() string {
+	return t.source
+}
+
+// HasFraction returns whether t shows fractions of a second.
+func (t *Template) HasFraction() bool {
+	for _, p := range t.parts {
+		if p.verb == 'f' {
+			return true
+		}
+	}
+	return false
+}
+
+// Format returns d formatted following t.
+func (t *Template) Format(d time.Duration) string {
+	var str strings.Builder
+	if d < 0 {
+		str.WriteByte('-')
+		d = -d
+	}
+	for _, p := range t.parts {
+		if p.verb == 0 {
+			str.WriteString(p.literal)
+			continue
+		}
+		unit := templateUnits[p.verb]
+		v := int64(d / unit)
+		// Only the largest unit holds the whole of the duration.
+		if unit != t.largest {
+			switch unit {
+			case time.Hour:
+				v %= 24
+			case time.Minute, time.Second:
+				v %= 60
+			case time.Second / 10:
+				v %= 10
+			}
+		}
+		if p.verb >= 'A' && p.verb <= 'Z' {
+			str.WriteString(fmt.Sprintf("%02d", v))
+		} else {
+			str.WriteString(fmt.Sprint(v))
+		}
+	}
+	return str.String()
+}
+
+// Seconds returns s seconds formatted following t.
+func (t *Template) Seconds(s int) string {
+	return t.Format(time.Duration(s) * time.Second)
+}
@@ -0,0 +1,94 @@
+package widget
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		src     string
+		d       time.Duration
+		want    string
+		wantErr bool
+	}{
+		{"%H:%M:%S", 90 * time.Minute, "01:30:00", false},
+		// The largest unit holds the whole duration.
+		{"%M:%S", time.Hour, "60:00", false},
+		{"%hh %mm", 90 * time.Minute, "1h 30m", false},
+		{"%Dd %H:%M:%S", 2*24*time.Hour + 3*time.Hour + 15*time.Minute, "02d 03:15:00", false},
+		{"%S.%f", 61500 * time.Millisecond, "61.5", false},
+		{"%M:%S", -65 * time.Second, "-01:05", false},
+		{"%S%%", 42 * time.Second, "42%", false},
+		{"%%%S", 7 * time.Second, "%07", false},
+		{"%%", time.Second, "%", false},
+		{"", time.Hour, "", false},
+		{"done", time.Hour, "done", false},
+		{"%S%", 0, "", true},
+		{"%", 0, "", true},
+		{"%x", 0, "", true},
+	}
+	for _, tt := range tests {
+		tmpl, err := ParseTemplate(tt.src)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTemplate(%q) error = %v, want error %v", tt.src, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := tmpl.Format(tt.d); got != tt.want {
+			t.Errorf("ParseTemplate(%q).Format(%v) = %q, want %q", tt.src, tt.d, got, tt.want)
+		}
+		if got := tmpl.String(); got != tt.src {
+			t.Errorf("ParseTemplate(%q).String() = %q", tt.src, got)
+		}
+	}
+}
+
+func TestTemplateHasFraction(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"%M:%S.%f", true},
+		{"%M:%S", false},
+		{"%%f", false},
+	}
+	for _, tt := range tests {
+		tmpl, err := ParseTemplate(tt.src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := tmpl.HasFraction(); got != tt.want {
+			t.Errorf("ParseTemplate(%q).HasFraction() = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestClockTemplateCountdown(t *testing.T) {
+	tests := []struct {
+		src  string
+		left time.Duration
+		want string
+	}{
+		// Like SecondWithColons, a count down shows the seconds begun,
+		// not those done.
+		{"%M:%S", 9950 * time.Millisecond, "00:10"},
+		{"%M:%S", 9 * time.Second, "00:09"},
+		{"%M:%S", 50 * time.Millisecond, "00:01"},
+		{"%M:%S.%f", 9950 * time.Millisecond, "00:09.9"},
+	}
+	for _, tt := range tests {
+		tmpl, err := ParseTemplate(tt.src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := NewTimer(10)
+		c.SetTemplate(tmpl)
+		c.Rewind(tt.left - 10*time.Second)
+		if got := c.text(); got != tt.want {
+			t.Errorf("%q with %v left = %q, want %q", tt.src, tt.left, got, tt.want)
+		}
+	}
+}
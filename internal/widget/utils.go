@@ -13,140 +13,6 @@ const (
 	AlignDown
 )
 
-// ANSI Shadow font. Font character are guranteed to have a length of 6
-// cells, and have all of the rows be equal in length.
-// Ref: https://raw.githubusercontent.com/anonymoushack47/ANSI-shadow/master/ansi-shadow.flf
-var ANSIShadow = map[rune][][]rune{
-	'0': {
-		[]rune(" ██████╗ "),
-		[]rune("██╔═████╗"),
-		[]rune("██║██╔██║"),
-		[]rune("████╔╝██║"),
-		[]rune("╚██████╔╝"),
-		[]rune(" ╚═════╝ "),
-	},
-	'1': {
-		[]rune(" ██╗"),
-		[]rune("███║"),
-		[]rune("╚██║"),
-		[]rune(" ██║"),
-		[]rune(" ██║"),
-		[]rune(" ╚═╝"),
-	},
-	'2': {
-		[]rune("██████╗ "),
-		[]rune("╚════██╗"),
-		[]rune(" █████╔╝"),
-		[]rune("██╔═══╝ "),
-		[]rune("███████╗"),
-		[]rune("╚══════╝"),
-	},
-	'3': {
-		[]rune("██████╗ "),
-		[]rune("╚════██╗"),
-		[]rune(" █████╔╝"),
-		[]rune(" ╚═══██╗"),
-		[]rune("██████╔╝"),
-		[]rune("╚═════╝ "),
-	},
-	'4': {
-		[]rune("██╗  ██╗"),
-		[]rune("██║  ██║"),
-		[]rune("███████║"),
-		[]rune("╚════██║"),
-		[]rune("     ██║"),
-		[]rune("     ╚═╝"),
-	},
-	'5': {
-		[]rune("███████╗"),
-		[]rune("██╔════╝"),
-		[]rune("███████╗"),
-		[]rune("╚════██║"),
-		[]rune("███████║"),
-		[]rune("╚══════╝"),
-	},
-	'6': {
-		[]rune(" ██████╗ "),
-		[]rune("██╔════╝ "),
-		[]rune("███████╗ "),
-		[]rune("██╔═══██╗"),
-		[]rune("╚██████╔╝"),
-		[]rune(" ╚═════╝ "),
-	},
-	'7': {
-		[]rune("███████╗"),
-		[]rune("╚════██║"),
-		[]rune("    ██╔╝"),
-		[]rune("   ██╔╝ "),
-		[]rune("   ██║  "),
-		[]rune("   ╚═╝  "),
-	},
-	'8': {
-		[]rune(" █████╗ "),
-		[]rune("██╔══██╗"),
-		[]rune("╚█████╔╝"),
-		[]rune("██╔══██╗"),
-		[]rune("╚█████╔╝"),
-		[]rune(" ╚════╝ "),
-	},
-	'9': {
-		[]rune(" █████╗ "),
-		[]rune("██╔══██╗"),
-		[]rune("╚██████║"),
-		[]rune(" ╚═══██║"),
-		[]rune(" █████╔╝"),
-		[]rune(" ╚════╝ "),
-	},
-	':': {
-		[]rune("   "),
-		[]rune("██╗"),
-		[]rune("╚═╝"),
-		[]rune("██╗"),
-		[]rune("╚═╝"),
-		[]rune("   "),
-	},
-	'h': {
-		[]rune("██╗  ██╗"),
-		[]rune("██║  ██║"),
-		[]rune("███████║"),
-		[]rune("██╔══██║"),
-		[]rune("██║  ██║"),
-		[]rune("╚═╝  ╚═╝"),
-	},
-	'm': {
-		[]rune("███╗   ███╗"),
-		[]rune("████╗ ████║"),
-		[]rune("██╔████╔██║"),
-		[]rune("██║╚██╔╝██║"),
-		[]rune("██║ ╚═╝ ██║"),
-		[]rune("╚═╝     ╚═╝"),
-	},
-	's': {
-		[]rune("███████╗"),
-		[]rune("██╔════╝"),
-		[]rune("███████╗"),
-		[]rune("╚════██║"),
-		[]rune("███████║"),
-		[]rune("╚══════╝"),
-	},
-	' ': {
-		[]rune("    "),
-		[]rune("    "),
-		[]rune("    "),
-		[]rune("    "),
-		[]rune("    "),
-		[]rune("    "),
-	},
-	'-': {
-		[]rune("       "),
-		[]rune("       "),
-		[]rune("██████╗"),
-		[]rune("╚═════╝"),
-		[]rune("       "),
-		[]rune("       "),
-	},
-}
-
 // getCenter returns the coordinate from where, if drawn, an object of
 // length reservedLen would looked centered on a screen of size
 // totalLen. Assuming 0 is the origin.
@@ -158,68 +24,119 @@ func getCenter(totalLen, reservedLen int) int {
 	return (totalLen - reservedLen) / 2
 }
 
-// decomposeSecond breaks seconds s into hours, minutes and seconds.
-func DecomposeSecond(s int) (hrs, min, sec int) {
-	return s / 3600, (s / 60) % 60, s % 60
+// DecomposeSecond breaks seconds s into days, hours, minutes and
+// seconds.
+func DecomposeSecond(s int) (days, hrs, min, sec int) {
+	return s / 86400, (s / 3600) % 24, (s / 60) % 60, s % 60
 }
 
-// SecondWithLetters formats seconds s as 'XXh XXm XXs' or 'XXm XXs' or
-// 'XXs'. Leading zeros are omitted.
+// SecondWithLetters formats seconds s as 'Xd XXh XXm XXs', 'Xh XXm
+// XXs', 'Xm XXs' or 'Xs'. The first group isn't zero padded, and the
+// groups after it are, to two digits.
 func SecondWithLetters(s int) string {
-	hrs, min, sec := DecomposeSecond(s)
-	var str strings.Builder
-	if hrs != 0 {
-		str.WriteString(fmt.Sprintf("%dh ", hrs))
+	days, hrs, min, sec := DecomposeSecond(s)
+	switch {
+	case days != 0:
+		return fmt.Sprintf("%dd %02dh %02dm %02ds", days, hrs, min, sec)
+	case hrs != 0:
+		return fmt.Sprintf("%dh %02dm %02ds", hrs, min, sec)
+	case min != 0:
+		return fmt.Sprintf("%dm %02ds", min, sec)
+	default:
+		return fmt.Sprintf("%ds", sec)
 	}
-	if hrs != 0 || min != 0 {
-		str.WriteString(fmt.Sprintf("%dm ", min))
+}
+
+// SecondWithLettersFixed formats seconds s like SecondWithLetters, but
+// keeps every group that longest would have, so that the text doesn't
+// change it's layout as s counts up to, or down from, longest. Groups
+// are zero padded, the first one to as many digits as it has in
+// longest. The groups of s itself are kept too, should it be longer.
+func SecondWithLettersFixed(s, longest int) string {
+	// s may have outgrown longest, like a stopwatch does.
+	if s > longest {
+		longest = s
+	}
+	days, hrs, min, sec := DecomposeSecond(s)
+	ldays, lhrs, lmin, lsec := DecomposeSecond(longest)
+	switch {
+	case ldays != 0:
+		return fmt.Sprintf("%0*dd %02dh %02dm %02ds", digits(ldays), days, hrs, min, sec)
+	case lhrs != 0:
+		return fmt.Sprintf("%0*dh %02dm %02ds", digits(lhrs), hrs, min, sec)
+	case lmin != 0:
+		return fmt.Sprintf("%0*dm %02ds", digits(lmin), min, sec)
+	default:
+		return fmt.Sprintf("%0*ds", digits(lsec), sec)
 	}
-	str.WriteString(fmt.Sprintf("%ds", sec))
-	return str.String()
 }
 
-// SecondWithColons formats seconds s as 'XX:XX:XX' or 'XX:XX'. Leading
-// zeros are not omitted.
+// SecondWithColons formats seconds s as 'Xd XX:XX:XX', 'XX:XX:XX' or
+// 'XX:XX'. Leading zeros are not omitted.
 func SecondWithColons(s int) string {
-	hrs, min, sec := DecomposeSecond(s)
+	days, hrs, min, sec := DecomposeSecond(s)
 	var str strings.Builder
-	if hrs != 0 {
-		str.WriteString(fmt.Sprintf( "%02d:", hrs))
+	if days != 0 {
+		str.WriteString(fmt.Sprintf("%dd ", days))
+	}
+	if days != 0 || hrs != 0 {
+		str.WriteString(fmt.Sprintf("%02d:", hrs))
 	}
 	str.WriteString(fmt.Sprintf("%02d:%02d", min, sec))
 	return str.String()
 }
 
-// SecondToANSIShadowWithLetters returns s in the format, 12h 34m 55s,
-// in ANSIShadow font. If hours in zero, then minutes will be omitted if
-// it is zero. If hours is not zero, minutes is not omitted.
-func SecondToANSIShadowWithLetters(s int) []string {
-	return stringToANSIShadow(SecondWithLetters(s))
+// DurationWithColons formats d like SecondWithColons, followed by it's
+// tenths of a second, like 01:23.4. A negative d has a leading '-'.
+func DurationWithColons(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	return fmt.Sprintf("%s%s.%d", sign, SecondWithColons(int(d/time.Second)), d%time.Second/(time.Second/10))
 }
 
-// SecondToANSIShadowWithColons formats seconds s as 'XX:XX:XX' or
-// 'XX:XX', without omitting leading zeros, and returns it in ANSI
-// Shadow font.
-func SecondToANSIShadowWithColons(s int) []string {
-	return stringToANSIShadow(SecondWithColons(s))
+// SecondWithColonsFixed formats seconds s like SecondWithColons, but
+// keeps the days and hours if longest would have them, so that the
+// text doesn't change it's layout as s counts up to, or down from,
+// longest. The days and hours of s itself are kept too, should it be
+// longer.
+func SecondWithColonsFixed(s, longest int) string {
+	if s > longest {
+		longest = s
+	}
+	days, hrs, min, sec := DecomposeSecond(s)
+	ldays, lhrs, _, _ := DecomposeSecond(longest)
+	switch {
+	case ldays != 0:
+		return fmt.Sprintf("%0*dd %02d:%02d:%02d", digits(ldays), days, hrs, min, sec)
+	case lhrs != 0:
+		return fmt.Sprintf("%02d:%02d:%02d", hrs, min, sec)
+	default:
+		return fmt.Sprintf("%02d:%02d", min, sec)
+	}
 }
 
-func stringToANSIShadow(str string) []string {
-	text := make([]string, 6)
-	for i := 0; i < len(text); i++ {
-		rs := []rune{}
-		for _, r := range str {
-			rs = append(rs, ANSIShadow[r][i]...)
-		}
-		text[i] = string(rs)
+// digits returns the number of decimal digits in n.
+func digits(n int) int {
+	d := 1
+	for n >= 10 || n <= -10 {
+		n /= 10
+		d++
 	}
-	return text
+	return d
 }
 
 // Worker executes work after every second. If a message is sent to
 // quit, Worker returns.
 func Worker(work func(), quit <-chan struct{}) {
-	t := time.NewTicker(1 * time.Second)
+	WorkerEvery(time.Second, work, quit)
+}
+
+// WorkerEvery executes work after every interval d. If a message is
+// sent to quit, WorkerEvery returns.
+func WorkerEvery(d time.Duration, work func(), quit <-chan struct{}) {
+	t := time.NewTicker(d)
 	defer t.Stop()
 
 	for {
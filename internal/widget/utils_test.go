@@ -0,0 +1,62 @@
+package widget
+
+import "testing"
+
+func TestSecondWithLetters(t *testing.T) {
+	tests := []struct {
+		s    int
+		want string
+	}{
+		{0, "0s"},
+		{5, "5s"},
+		{65, "1m 05s"},
+		{3605, "1h 00m 05s"},
+		{184500, "2d 03h 15m 00s"},
+		{86400, "1d 00h 00m 00s"},
+	}
+	for _, tt := range tests {
+		if got := SecondWithLetters(tt.s); got != tt.want {
+			t.Errorf("SecondWithLetters(%d) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestSecondWithColonsFixed(t *testing.T) {
+	tests := []struct {
+		s, longest int
+		want       string
+	}{
+		{0, 59, "00:00"},
+		{65, 3600, "00:01:05"},
+		{3600, 3600, "01:00:00"},
+		{59, 86400, "0d 00:00:59"},
+		// s longer than longest keeps it's own days and hours.
+		{184500, 3600, "2d 03:15:00"},
+		{259200, 3600, "3d 00:00:00"},
+		{3661, 60, "01:01:01"},
+	}
+	for _, tt := range tests {
+		if got := SecondWithColonsFixed(tt.s, tt.longest); got != tt.want {
+			t.Errorf("SecondWithColonsFixed(%d, %d) = %q, want %q", tt.s, tt.longest, got, tt.want)
+		}
+	}
+}
+
+func TestSecondWithLettersFixed(t *testing.T) {
+	tests := []struct {
+		s, longest int
+		want       string
+	}{
+		{5, 30, "05s"},
+		{5, 600, "00m 05s"},
+		{3600, 36000, "01h 00m 00s"},
+		{184500, 3600, "2d 03h 15m 00s"},
+		{259200, 3600, "3d 00h 00m 00s"},
+		{61, 5, "1m 01s"},
+	}
+	for _, tt := range tests {
+		if got := SecondWithLettersFixed(tt.s, tt.longest); got != tt.want {
+			t.Errorf("SecondWithLettersFixed(%d, %d) = %q, want %q", tt.s, tt.longest, got, tt.want)
+		}
+	}
+}